@@ -0,0 +1,234 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package cf
+
+import (
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/cli/plugin"
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pkg/errors"
+)
+
+type Client struct {
+	cliConnection plugin.CliConnection
+}
+
+func NewClient(cliConnection plugin.CliConnection) *Client {
+	return &Client{
+		cliConnection: cliConnection,
+	}
+}
+
+func (c *Client) cfClient() (*cfclient.Client, error) {
+	apiEndpoint, err := c.cliConnection.ApiEndpoint()
+	if err != nil {
+		return nil, errors.Wrap(err, "error determining API endpoint")
+	}
+
+	accessToken, err := c.cliConnection.AccessToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "error determining access token")
+	}
+
+	skipSSLValidation, err := c.cliConnection.IsSSLDisabled()
+	if err != nil {
+		return nil, errors.Wrap(err, "error determining SSL validation setting")
+	}
+
+	return cfclient.NewClient(&cfclient.Config{
+		ApiAddress:        apiEndpoint,
+		Token:             strings.TrimPrefix(accessToken, "bearer "),
+		SkipSslValidation: skipSSLValidation,
+	})
+}
+
+func (c *Client) ServiceExists(serviceName string) bool {
+	_, err := c.cliConnection.CliCommandWithoutTerminalOutput("service", serviceName)
+	return err == nil
+}
+
+func (c *Client) CreateServiceInstance(planType, instanceName string) error {
+	_, err := c.cliConnection.CliCommand("create-service", "p.mysql", planType, instanceName)
+	return err
+}
+
+func (c *Client) GetHostnames(instanceName string) ([]string, error) {
+	output, err := c.cliConnection.CliCommandWithoutTerminalOutput("service-key", instanceName, "vm-credentials")
+	if err != nil {
+		_, err = c.cliConnection.CliCommandWithoutTerminalOutput("create-service-key", instanceName, "vm-credentials")
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating service key")
+		}
+
+		output, err = c.cliConnection.CliCommandWithoutTerminalOutput("service-key", instanceName, "vm-credentials")
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching service key")
+		}
+	}
+
+	return output, nil
+}
+
+func (c *Client) UpdateServiceConfig(instanceName string, jsonParams string) error {
+	_, err := c.cliConnection.CliCommand("update-service", instanceName, "-c", jsonParams)
+	return err
+}
+
+func (c *Client) BindService(appName, serviceName string) error {
+	_, err := c.cliConnection.CliCommand("bind-service", appName, serviceName)
+	return err
+}
+
+func (c *Client) DeleteApp(appName string) error {
+	_, err := c.cliConnection.CliCommand("delete", appName, "-f", "-r")
+	return err
+}
+
+func (c *Client) DeleteServiceInstance(instanceName string) error {
+	_, err := c.cliConnection.CliCommand("delete-service", instanceName, "-f")
+	return err
+}
+
+func (c *Client) DumpLogs(appName string) {
+	c.cliConnection.CliCommand("logs", appName, "--recent")
+}
+
+func (c *Client) GetRecentLogs(appName string) (string, error) {
+	output, err := c.cliConnection.CliCommandWithoutTerminalOutput("logs", appName, "--recent")
+	if err != nil {
+		return "", errors.Wrapf(err, "error fetching recent logs for %q", appName)
+	}
+
+	return strings.Join(output, "\n"), nil
+}
+
+func (c *Client) PushApp(path, appName string) error {
+	_, err := c.cliConnection.CliCommand("push", appName, "-p", path, "--no-start", "-b", "binary_buildpack", "-c", "true", "-u", "none")
+	return err
+}
+
+func (c *Client) RenameService(oldName, newName string) error {
+	_, err := c.cliConnection.CliCommand("rename-service", oldName, newName)
+	return err
+}
+
+func (c *Client) RunTask(appName, command string) error {
+	_, err := c.cliConnection.CliCommand("run-task", appName, command)
+	return err
+}
+
+func (c *Client) StartApp(appName string) error {
+	_, err := c.cliConnection.CliCommand("start", appName)
+	return err
+}
+
+func (c *Client) ListServicesByQuery(query url.Values) ([]cfclient.Service, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListServicesByQuery(query)
+}
+
+func (c *Client) ListServicePlansByQuery(query url.Values) ([]cfclient.ServicePlan, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListServicePlansByQuery(query)
+}
+
+func (c *Client) ListServiceInstancesByQuery(query url.Values) ([]cfclient.ServiceInstance, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListServiceInstancesByQuery(query)
+}
+
+func (c *Client) ListServiceBindingsByQuery(query url.Values) ([]cfclient.ServiceBinding, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListServiceBindingsByQuery(query)
+}
+
+func (c *Client) ListServiceKeysByQuery(query url.Values) ([]cfclient.ServiceKey, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListServiceKeysByQuery(query)
+}
+
+func (c *Client) ListUserProvidedServiceInstancesByQuery(query url.Values) ([]cfclient.UserProvidedServiceInstance, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListUserProvidedServiceInstancesByQuery(query)
+}
+
+func (c *Client) GetAppByGuid(appGuid string) (cfclient.App, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return cfclient.App{}, err
+	}
+
+	return client.GetAppByGuid(appGuid)
+}
+
+func (c *Client) GetSpaceByGuid(spaceGuid string) (cfclient.Space, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return cfclient.Space{}, err
+	}
+
+	return client.GetSpaceByGuid(spaceGuid)
+}
+
+func (c *Client) GetOrgByGuid(orgGuid string) (cfclient.Org, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return cfclient.Org{}, err
+	}
+
+	return client.GetOrgByGuid(orgGuid)
+}
+
+func (c *Client) GetOrgByName(orgName string) (cfclient.Org, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return cfclient.Org{}, err
+	}
+
+	return client.GetOrgByName(orgName)
+}
+
+func (c *Client) GetSpaceByName(spaceName, orgGuid string) (cfclient.Space, error) {
+	client, err := c.cfClient()
+	if err != nil {
+		return cfclient.Space{}, err
+	}
+
+	return client.GetSpaceByName(spaceName, orgGuid)
+}
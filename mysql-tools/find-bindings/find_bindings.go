@@ -0,0 +1,498 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package find_bindings
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultGuidCacheSize bounds the space/org memoization caches; foundations
+// rarely have more distinct spaces or orgs than this in a single mysql-tools run.
+const defaultGuidCacheSize = 1024
+
+//go:generate counterfeiter . CFClient
+type CFClient interface {
+	ListServicesByQuery(url.Values) ([]cfclient.Service, error)
+	ListServicePlansByQuery(url.Values) ([]cfclient.ServicePlan, error)
+	ListServiceInstancesByQuery(url.Values) ([]cfclient.ServiceInstance, error)
+	ListServiceBindingsByQuery(url.Values) ([]cfclient.ServiceBinding, error)
+	ListServiceKeysByQuery(url.Values) ([]cfclient.ServiceKey, error)
+	ListUserProvidedServiceInstancesByQuery(url.Values) ([]cfclient.UserProvidedServiceInstance, error)
+	GetAppByGuid(appGuid string) (cfclient.App, error)
+	GetSpaceByGuid(spaceGuid string) (cfclient.Space, error)
+	GetOrgByGuid(orgGuid string) (cfclient.Org, error)
+	GetOrgByName(orgName string) (cfclient.Org, error)
+	GetSpaceByName(spaceName, orgGuid string) (cfclient.Space, error)
+}
+
+const (
+	AppBinding          = "AppBinding"
+	ServiceKeyBinding   = "ServiceKeyBinding"
+	UserProvidedBinding = "UserProvidedBinding"
+	OrphanBinding       = "Orphan"
+)
+
+// FindBindingsOptions controls optional, off-by-default behavior of
+// BindingFinder.FindBindings.
+type FindBindingsOptions struct {
+	// IncludeOrphans makes FindBindings emit one Orphan Binding per instance
+	// that has zero apps and zero service keys bound to it, instead of
+	// silently omitting it.
+	IncludeOrphans bool
+}
+
+type Binding struct {
+	Name                string
+	ServiceInstanceName string
+	ServiceInstanceGuid string
+	OrgName             string
+	SpaceName           string
+	Type                string
+
+	// Host and Port are only populated for UserProvidedBinding entries, where
+	// they're read out of the UPSI's credentials so migration tooling can
+	// decide whether the external MySQL they point at needs to be rerouted.
+	Host string
+	Port int
+}
+
+// Option configures a BindingFinder constructed via NewBindingFinder.
+type Option func(*BindingFinder)
+
+// WithConcurrency bounds how many service instances BindingFinder.FindBindings
+// inspects at once. The default, 1, preserves strictly sequential discovery.
+func WithConcurrency(n int) Option {
+	return func(bf *BindingFinder) {
+		if n > 0 {
+			bf.concurrency = n
+		}
+	}
+}
+
+// WithUserProvidedMatcher makes FindBindings also list user-provided service
+// instances and include any that matcher reports as referencing this MySQL,
+// as UserProvidedBinding entries. Without this option, user-provided
+// instances are never inspected.
+func WithUserProvidedMatcher(matcher func(cfclient.UserProvidedServiceInstance) bool) Option {
+	return func(bf *BindingFinder) {
+		bf.userProvidedMatcher = matcher
+	}
+}
+
+type BindingFinder struct {
+	cfClient            CFClient
+	concurrency         int
+	spaceCache          *lruCache
+	orgCache            *lruCache
+	userProvidedMatcher func(cfclient.UserProvidedServiceInstance) bool
+}
+
+func NewBindingFinder(cfClient CFClient, opts ...Option) *BindingFinder {
+	bf := &BindingFinder{
+		cfClient:    cfClient,
+		concurrency: 1,
+		spaceCache:  newLRUCache(defaultGuidCacheSize),
+		orgCache:    newLRUCache(defaultGuidCacheSize),
+	}
+
+	for _, opt := range opts {
+		opt(bf)
+	}
+
+	return bf
+}
+
+// scopeFilter reports whether a space (identified by guid) falls within the
+// scope a FindBindingsIn* method was asked to search. A nil scopeFilter
+// matches every space.
+type scopeFilter func(spaceGuid string) (bool, error)
+
+// instanceQuery builds the query used to list service instances for a given
+// plan guid. FindBindingsInSpace overrides this to also filter by space_guid
+// at the API level, so it never has to enumerate instances outside that space.
+type instanceQuery func(planGuid string) url.Values
+
+func defaultInstanceQuery(planGuid string) url.Values {
+	return queryFor("service_plan_guid", planGuid)
+}
+
+func (bf *BindingFinder) FindBindings(serviceLabel string, opts FindBindingsOptions) ([]Binding, error) {
+	return bf.findBindings(serviceLabel, opts, nil, defaultInstanceQuery)
+}
+
+// FindBindingsInOrg is FindBindings narrowed to instances whose space belongs
+// to the named org. Every instance of the service is still listed and its
+// space looked up, but bindings and keys are only fetched for instances in
+// that org.
+func (bf *BindingFinder) FindBindingsInOrg(serviceLabel, orgName string, opts FindBindingsOptions) ([]Binding, error) {
+	org, err := bf.cfClient.GetOrgByName(orgName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error looking up org %q", orgName)
+	}
+
+	return bf.findBindings(serviceLabel, opts, func(spaceGuid string) (bool, error) {
+		space, err := bf.getSpace(spaceGuid)
+		if err != nil {
+			return false, err
+		}
+
+		return space.OrganizationGuid == org.Guid, nil
+	}, defaultInstanceQuery)
+}
+
+// FindBindingsInSpace is FindBindings narrowed to a single space within orgGuid.
+func (bf *BindingFinder) FindBindingsInSpace(serviceLabel, orgGuid, spaceName string, opts FindBindingsOptions) ([]Binding, error) {
+	space, err := bf.cfClient.GetSpaceByName(spaceName, orgGuid)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error looking up space %q", spaceName)
+	}
+
+	return bf.findBindings(serviceLabel, opts, func(spaceGuid string) (bool, error) {
+		return spaceGuid == space.Guid, nil
+	}, func(planGuid string) url.Values {
+		return queryForSpace("service_plan_guid", planGuid, space.Guid)
+	})
+}
+
+func (bf *BindingFinder) findBindings(serviceLabel string, opts FindBindingsOptions, scope scopeFilter, buildInstanceQuery instanceQuery) ([]Binding, error) {
+	services, err := bf.cfClient.ListServicesByQuery(queryFor("label", serviceLabel))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error looking up service %q", serviceLabel)
+	}
+
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no service offering found with label %q", serviceLabel)
+	}
+
+	plans, err := bf.cfClient.ListServicePlansByQuery(queryFor("service_guid", services[0].Guid))
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing service plans")
+	}
+
+	var instances []cfclient.ServiceInstance
+	for _, plan := range plans {
+		planInstances, err := bf.listServiceInstances(buildInstanceQuery(plan.Guid))
+		if err != nil {
+			return nil, errors.Wrap(err, "error listing service instances")
+		}
+
+		instances = append(instances, planInstances...)
+	}
+
+	instances, err = bf.filterInScope(instances, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings, err := bf.findBindingsForInstances(instances, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	userProvidedBindings, err := bf.findUserProvidedBindings(scope)
+	if err != nil {
+		return nil, err
+	}
+	bindings = append(bindings, userProvidedBindings...)
+
+	sort.Slice(bindings, func(i, j int) bool {
+		if bindings[i].ServiceInstanceGuid != bindings[j].ServiceInstanceGuid {
+			return bindings[i].ServiceInstanceGuid < bindings[j].ServiceInstanceGuid
+		}
+		if bindings[i].Type != bindings[j].Type {
+			return bindings[i].Type < bindings[j].Type
+		}
+		return bindings[i].Name < bindings[j].Name
+	})
+
+	return bindings, nil
+}
+
+// filterInScope drops every instance whose space scope rejects, leaving
+// instances unchanged when scope is nil.
+func (bf *BindingFinder) filterInScope(instances []cfclient.ServiceInstance, scope scopeFilter) ([]cfclient.ServiceInstance, error) {
+	if scope == nil {
+		return instances, nil
+	}
+
+	var scoped []cfclient.ServiceInstance
+	for _, instance := range instances {
+		ok, err := scope(instance.SpaceGuid)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			scoped = append(scoped, instance)
+		}
+	}
+
+	return scoped, nil
+}
+
+// findBindingsForInstances runs findBindingsForInstance across every instance,
+// bounded by bf.concurrency. At the default concurrency of 1 this is a plain
+// sequential loop, which keeps discovery order (and the order the CFClient sees
+// calls in) identical to before concurrency support was added.
+func (bf *BindingFinder) findBindingsForInstances(instances []cfclient.ServiceInstance, opts FindBindingsOptions) ([]Binding, error) {
+	if bf.concurrency <= 1 {
+		var bindings []Binding
+		for _, instance := range instances {
+			instanceBindings, err := bf.findBindingsForInstance(instance, opts)
+			if err != nil {
+				return nil, err
+			}
+			bindings = append(bindings, instanceBindings...)
+		}
+
+		return bindings, nil
+	}
+
+	results := make([][]Binding, len(instances))
+	sem := make(chan struct{}, bf.concurrency)
+	g, ctx := errgroup.WithContext(context.Background())
+
+	for i, instance := range instances {
+		i, instance := i, instance
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+
+			instanceBindings, err := bf.findBindingsForInstance(instance, opts)
+			if err != nil {
+				return err
+			}
+
+			results[i] = instanceBindings
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var bindings []Binding
+	for _, instanceBindings := range results {
+		bindings = append(bindings, instanceBindings...)
+	}
+
+	return bindings, nil
+}
+
+func (bf *BindingFinder) findBindingsForInstance(instance cfclient.ServiceInstance, opts FindBindingsOptions) ([]Binding, error) {
+	serviceBindings, err := bf.listServiceBindings(queryFor("service_instance_guid", instance.Guid))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing service bindings for instance %q", instance.Name)
+	}
+
+	serviceKeys, err := bf.listServiceKeys(queryFor("service_instance_guid", instance.Guid))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing service keys for instance %q", instance.Name)
+	}
+
+	orphaned := len(serviceBindings) == 0 && len(serviceKeys) == 0
+	if orphaned && !opts.IncludeOrphans {
+		return nil, nil
+	}
+
+	space, err := bf.getSpace(instance.SpaceGuid)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error looking up space for instance %q", instance.Name)
+	}
+
+	org, err := bf.getOrg(space.OrganizationGuid)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error looking up org for instance %q", instance.Name)
+	}
+
+	if orphaned {
+		return []Binding{
+			{
+				Name:                "",
+				ServiceInstanceName: instance.Name,
+				ServiceInstanceGuid: instance.Guid,
+				OrgName:             org.Name,
+				SpaceName:           space.Name,
+				Type:                OrphanBinding,
+			},
+		}, nil
+	}
+
+	var bindings []Binding
+	for _, binding := range serviceBindings {
+		app, err := bf.cfClient.GetAppByGuid(binding.AppGuid)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error looking up app bound to instance %q", instance.Name)
+		}
+
+		bindings = append(bindings, Binding{
+			Name:                app.Name,
+			ServiceInstanceName: instance.Name,
+			ServiceInstanceGuid: instance.Guid,
+			OrgName:             org.Name,
+			SpaceName:           space.Name,
+			Type:                AppBinding,
+		})
+	}
+
+	for _, key := range serviceKeys {
+		bindings = append(bindings, Binding{
+			Name:                key.Name,
+			ServiceInstanceName: instance.Name,
+			ServiceInstanceGuid: instance.Guid,
+			OrgName:             org.Name,
+			SpaceName:           space.Name,
+			Type:                ServiceKeyBinding,
+		})
+	}
+
+	return bindings, nil
+}
+
+// findUserProvidedBindings lists every user-provided service instance and
+// reports the ones bf.userProvidedMatcher accepts as UserProvidedBinding
+// entries. It's a no-op unless WithUserProvidedMatcher was supplied, since
+// listing every UPSI on a foundation is otherwise wasted work.
+func (bf *BindingFinder) findUserProvidedBindings(scope scopeFilter) ([]Binding, error) {
+	if bf.userProvidedMatcher == nil {
+		return nil, nil
+	}
+
+	instances, err := bf.listUserProvidedServiceInstances(url.Values{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing user-provided service instances")
+	}
+
+	var bindings []Binding
+	for _, instance := range instances {
+		if !bf.userProvidedMatcher(instance) {
+			continue
+		}
+
+		if scope != nil {
+			ok, err := scope(instance.SpaceGuid)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		space, err := bf.getSpace(instance.SpaceGuid)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error looking up space for user-provided instance %q", instance.Name)
+		}
+
+		org, err := bf.getOrg(space.OrganizationGuid)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error looking up org for user-provided instance %q", instance.Name)
+		}
+
+		host, port := credentialedHostPort(instance.Credentials)
+
+		bindings = append(bindings, Binding{
+			Name:                instance.Name,
+			ServiceInstanceName: instance.Name,
+			ServiceInstanceGuid: instance.Guid,
+			OrgName:             org.Name,
+			SpaceName:           space.Name,
+			Type:                UserProvidedBinding,
+			Host:                host,
+			Port:                port,
+		})
+	}
+
+	return bindings, nil
+}
+
+// credentialedHostPort pulls a hostname and port out of a UPSI's free-form
+// credentials, tolerating the shapes operators commonly use ("hostname" or
+// "host", and a port expressed as either a number or a numeric string).
+func credentialedHostPort(credentials map[string]interface{}) (string, int) {
+	host, _ := credentials["hostname"].(string)
+	if host == "" {
+		host, _ = credentials["host"].(string)
+	}
+
+	var port int
+	switch p := credentials["port"].(type) {
+	case float64:
+		port = int(p)
+	case string:
+		port, _ = strconv.Atoi(p)
+	}
+
+	return host, port
+}
+
+// getSpace looks up a space by guid, memoizing the result so later lookups of
+// a space already fetched don't call GetSpaceByGuid again. Lookups of the same
+// space running concurrently before the first one completes each make the call.
+func (bf *BindingFinder) getSpace(spaceGuid string) (cfclient.Space, error) {
+	if cached, ok := bf.spaceCache.Get(spaceGuid); ok {
+		return cached.(cfclient.Space), nil
+	}
+
+	space, err := bf.cfClient.GetSpaceByGuid(spaceGuid)
+	if err != nil {
+		return cfclient.Space{}, err
+	}
+
+	bf.spaceCache.Put(spaceGuid, space)
+	return space, nil
+}
+
+// getOrg looks up an org by guid, memoizing the result so later lookups of an
+// org already fetched don't call GetOrgByGuid again. Lookups of the same org
+// running concurrently before the first one completes each make the call.
+func (bf *BindingFinder) getOrg(orgGuid string) (cfclient.Org, error) {
+	if cached, ok := bf.orgCache.Get(orgGuid); ok {
+		return cached.(cfclient.Org), nil
+	}
+
+	org, err := bf.cfClient.GetOrgByGuid(orgGuid)
+	if err != nil {
+		return cfclient.Org{}, err
+	}
+
+	bf.orgCache.Put(orgGuid, org)
+	return org, nil
+}
+
+func queryFor(field, value string) url.Values {
+	q := url.Values{}
+	q.Set("q", fmt.Sprintf("%s:%s", field, value))
+	return q
+}
+
+// queryForSpace is queryFor with an additional space_guid condition ANDed on,
+// so a single list call can be scoped to one space instead of the whole
+// foundation.
+func queryForSpace(field, value, spaceGuid string) url.Values {
+	q := url.Values{}
+	q.Set("q", fmt.Sprintf("%s:%s;space_guid:%s", field, value, spaceGuid))
+	return q
+}
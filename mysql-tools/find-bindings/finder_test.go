@@ -1,7 +1,10 @@
 package find_bindings_test
 
 import (
+	"fmt"
 	"net/url"
+	"sync/atomic"
+	"time"
 
 	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	. "github.com/onsi/ginkgo"
@@ -162,7 +165,7 @@ var _ = Describe("BindingFinder", func() {
 
 		It("returns a list of applications and service keys associated with the service", func() {
 			finder := find_bindings.NewBindingFinder(fakeCFClient)
-			listOfBindings, err := finder.FindBindings(serviceName)
+			listOfBindings, err := finder.FindBindings(serviceName, find_bindings.FindBindingsOptions{})
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(fakeCFClient.ListServicesByQueryCallCount()).To(Equal(1))
@@ -229,4 +232,382 @@ var _ = Describe("BindingFinder", func() {
 			Expect(listOfBindings).To(Equal(expectedBindings))
 		})
 	})
+
+	Context("WithConcurrency", func() {
+		var fakeCFClient *findbindingsfakes.FakeCFClient
+		var maxInFlight *int32
+
+		BeforeEach(func() {
+			fakeCFClient = &findbindingsfakes.FakeCFClient{}
+			fakeCFClient.ListServicesByQueryReturns([]cfclient.Service{{Label: "p.mysql", Guid: "service-guid"}}, nil)
+			fakeCFClient.ListServicePlansByQueryReturns([]cfclient.ServicePlan{
+				{Name: "small", Guid: "small-guid", ServiceGuid: "service-guid"},
+			}, nil)
+
+			var instances []cfclient.ServiceInstance
+			for i := 0; i < 5; i++ {
+				instances = append(instances, cfclient.ServiceInstance{
+					Name:      fmt.Sprintf("instance%d", i),
+					Guid:      fmt.Sprintf("instance%d-guid", i),
+					SpaceGuid: "space-guid",
+				})
+			}
+			fakeCFClient.ListServiceInstancesByQueryReturns(instances, nil)
+
+			var inFlight int32
+			maxInFlight = new(int32)
+			fakeCFClient.ListServiceBindingsByQueryStub = func(url.Values) ([]cfclient.ServiceBinding, error) {
+				current := atomic.AddInt32(&inFlight, 1)
+				for {
+					observed := atomic.LoadInt32(maxInFlight)
+					if current <= observed || atomic.CompareAndSwapInt32(maxInFlight, observed, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				return nil, nil
+			}
+			fakeCFClient.ListServiceKeysByQueryReturns(nil, nil)
+		})
+
+		It("never runs more instances concurrently than the configured concurrency", func() {
+			finder := find_bindings.NewBindingFinder(fakeCFClient, find_bindings.WithConcurrency(2))
+			_, err := finder.FindBindings("p.mysql", find_bindings.FindBindingsOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(atomic.LoadInt32(maxInFlight)).To(BeNumerically("<=", 2))
+			Expect(fakeCFClient.ListServiceBindingsByQueryCallCount()).To(Equal(5))
+		})
+	})
+
+	Context("pagination", func() {
+		var fakeCFClient *findbindingsfakes.FakeCFClient
+
+		pageOfInstances := func(prefix string, size int) []cfclient.ServiceInstance {
+			instances := []cfclient.ServiceInstance{
+				{Name: prefix, Guid: prefix + "-guid", SpaceGuid: prefix + "-space-guid"},
+			}
+			for i := 1; i < size; i++ {
+				instances = append(instances, cfclient.ServiceInstance{
+					Name: fmt.Sprintf("%s-filler-%d", prefix, i),
+					Guid: fmt.Sprintf("%s-filler-%d-guid", prefix, i),
+				})
+			}
+			return instances
+		}
+
+		BeforeEach(func() {
+			fakeCFClient = &findbindingsfakes.FakeCFClient{}
+			fakeCFClient.ListServicesByQueryReturns([]cfclient.Service{{Label: "p.mysql", Guid: "service-guid"}}, nil)
+			fakeCFClient.ListServicePlansByQueryReturns([]cfclient.ServicePlan{
+				{Name: "small", Guid: "small-guid", ServiceGuid: "service-guid"},
+			}, nil)
+
+			fakeCFClient.ListServiceInstancesByQueryReturnsOnCall(0, pageOfInstances("page1", 50), nil)
+			fakeCFClient.ListServiceInstancesByQueryReturnsOnCall(1, pageOfInstances("page2", 50), nil)
+			fakeCFClient.ListServiceInstancesByQueryReturnsOnCall(2, pageOfInstances("page3", 3), nil)
+
+			fakeCFClient.ListServiceBindingsByQueryStub = func(query url.Values) ([]cfclient.ServiceBinding, error) {
+				switch query.Get("q") {
+				case "service_instance_guid:page1-guid":
+					return []cfclient.ServiceBinding{{Guid: "binding1", AppGuid: "app-guid", ServiceInstanceGuid: "page1-guid"}}, nil
+				case "service_instance_guid:page2-guid":
+					return []cfclient.ServiceBinding{{Guid: "binding2", AppGuid: "app-guid", ServiceInstanceGuid: "page2-guid"}}, nil
+				case "service_instance_guid:page3-guid":
+					return []cfclient.ServiceBinding{{Guid: "binding3", AppGuid: "app-guid", ServiceInstanceGuid: "page3-guid"}}, nil
+				default:
+					return nil, nil
+				}
+			}
+			fakeCFClient.ListServiceKeysByQueryReturns(nil, nil)
+			fakeCFClient.GetAppByGuidReturns(cfclient.App{Guid: "app-guid", Name: "app"}, nil)
+			fakeCFClient.GetSpaceByGuidReturns(cfclient.Space{Name: "space", OrganizationGuid: "org-guid"}, nil)
+			fakeCFClient.GetOrgByGuidReturns(cfclient.Org{Name: "org"}, nil)
+		})
+
+		It("follows every page of service instances and collects bindings from each one", func() {
+			finder := find_bindings.NewBindingFinder(fakeCFClient)
+			bindings, err := finder.FindBindings("p.mysql", find_bindings.FindBindingsOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeCFClient.ListServiceInstancesByQueryCallCount()).To(Equal(3))
+
+			firstPageQuery := fakeCFClient.ListServiceInstancesByQueryArgsForCall(0)
+			Expect(firstPageQuery.Get("page")).To(Equal(""))
+
+			secondPageQuery := fakeCFClient.ListServiceInstancesByQueryArgsForCall(1)
+			Expect(secondPageQuery.Get("page")).To(Equal("2"))
+			Expect(secondPageQuery.Get("q")).To(Equal("service_plan_guid:small-guid"))
+
+			thirdPageQuery := fakeCFClient.ListServiceInstancesByQueryArgsForCall(2)
+			Expect(thirdPageQuery.Get("page")).To(Equal("3"))
+
+			var guids []string
+			for _, b := range bindings {
+				guids = append(guids, b.ServiceInstanceGuid)
+			}
+			Expect(guids).To(ConsistOf("page1-guid", "page2-guid", "page3-guid"))
+		})
+	})
+
+	Context("memoizing space and org lookups", func() {
+		var fakeCFClient *findbindingsfakes.FakeCFClient
+
+		BeforeEach(func() {
+			fakeCFClient = &findbindingsfakes.FakeCFClient{}
+			fakeCFClient.ListServicesByQueryReturns([]cfclient.Service{{Label: "p.mysql", Guid: "service-guid"}}, nil)
+			fakeCFClient.ListServicePlansByQueryReturns([]cfclient.ServicePlan{
+				{Name: "small", Guid: "small-guid", ServiceGuid: "service-guid"},
+			}, nil)
+
+			fakeCFClient.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{
+				{Name: "instance1", Guid: "instance1-guid", SpaceGuid: "shared-space-guid"},
+				{Name: "instance2", Guid: "instance2-guid", SpaceGuid: "shared-space-guid"},
+			}, nil)
+
+			fakeCFClient.ListServiceBindingsByQueryReturns([]cfclient.ServiceBinding{
+				{Guid: "binding-guid", AppGuid: "app-guid", ServiceInstanceGuid: "instance-guid"},
+			}, nil)
+			fakeCFClient.ListServiceKeysByQueryReturns(nil, nil)
+			fakeCFClient.GetAppByGuidReturns(cfclient.App{Guid: "app-guid", Name: "app"}, nil)
+			fakeCFClient.GetSpaceByGuidReturns(cfclient.Space{Name: "shared-space", OrganizationGuid: "shared-org-guid"}, nil)
+			fakeCFClient.GetOrgByGuidReturns(cfclient.Org{Name: "shared-org"}, nil)
+		})
+
+		It("only looks up a shared space and org once", func() {
+			finder := find_bindings.NewBindingFinder(fakeCFClient)
+			_, err := finder.FindBindings("p.mysql", find_bindings.FindBindingsOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeCFClient.GetSpaceByGuidCallCount()).To(Equal(1))
+			Expect(fakeCFClient.GetOrgByGuidCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("user-provided service instances", func() {
+		var fakeCFClient *findbindingsfakes.FakeCFClient
+
+		BeforeEach(func() {
+			fakeCFClient = &findbindingsfakes.FakeCFClient{}
+			fakeCFClient.ListServicesByQueryReturns([]cfclient.Service{{Label: "p.mysql", Guid: "service-guid"}}, nil)
+			fakeCFClient.ListServicePlansByQueryReturns(nil, nil)
+
+			fakeCFClient.ListUserProvidedServiceInstancesByQueryReturns([]cfclient.UserProvidedServiceInstance{
+				{
+					Name:      "external-mysql",
+					Guid:      "external-mysql-guid",
+					SpaceGuid: "external-space-guid",
+					Credentials: map[string]interface{}{
+						"hostname": "mysql.example.com",
+						"port":     float64(3306),
+					},
+				},
+				{
+					Name:      "unrelated-upsi",
+					Guid:      "unrelated-upsi-guid",
+					SpaceGuid: "external-space-guid",
+					Credentials: map[string]interface{}{
+						"uri": "https://example.com/api",
+					},
+				},
+			}, nil)
+
+			fakeCFClient.GetSpaceByGuidReturns(cfclient.Space{Name: "external-space", OrganizationGuid: "external-org-guid"}, nil)
+			fakeCFClient.GetOrgByGuidReturns(cfclient.Org{Name: "external-org"}, nil)
+		})
+
+		It("does not list user-provided service instances unless a matcher is supplied", func() {
+			finder := find_bindings.NewBindingFinder(fakeCFClient)
+			_, err := finder.FindBindings("p.mysql", find_bindings.FindBindingsOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeCFClient.ListUserProvidedServiceInstancesByQueryCallCount()).To(Equal(0))
+		})
+
+		It("includes only the user-provided instances the matcher accepts, with their host and port", func() {
+			matcher := func(upsi cfclient.UserProvidedServiceInstance) bool {
+				_, hasHostname := upsi.Credentials["hostname"]
+				return hasHostname
+			}
+
+			finder := find_bindings.NewBindingFinder(fakeCFClient, find_bindings.WithUserProvidedMatcher(matcher))
+			bindings, err := finder.FindBindings("p.mysql", find_bindings.FindBindingsOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bindings).To(Equal([]find_bindings.Binding{
+				{
+					Name:                "external-mysql",
+					ServiceInstanceName: "external-mysql",
+					ServiceInstanceGuid: "external-mysql-guid",
+					OrgName:             "external-org",
+					SpaceName:           "external-space",
+					Type:                "UserProvidedBinding",
+					Host:                "mysql.example.com",
+					Port:                3306,
+				},
+			}))
+		})
+	})
+
+	Context("orphaned service instances", func() {
+		var fakeCFClient *findbindingsfakes.FakeCFClient
+
+		BeforeEach(func() {
+			fakeCFClient = &findbindingsfakes.FakeCFClient{}
+			fakeCFClient.ListServicesByQueryReturns([]cfclient.Service{{Label: "p.mysql", Guid: "service-guid"}}, nil)
+			fakeCFClient.ListServicePlansByQueryReturns([]cfclient.ServicePlan{
+				{Name: "small", Guid: "small-guid", ServiceGuid: "service-guid"},
+			}, nil)
+
+			fakeCFClient.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{
+				{Name: "orphan-instance", Guid: "orphan-instance-guid", SpaceGuid: "orphan-space-guid"},
+			}, nil)
+
+			fakeCFClient.ListServiceBindingsByQueryReturns(nil, nil)
+			fakeCFClient.ListServiceKeysByQueryReturns(nil, nil)
+			fakeCFClient.GetSpaceByGuidReturns(cfclient.Space{Name: "orphan-space", OrganizationGuid: "orphan-org-guid"}, nil)
+			fakeCFClient.GetOrgByGuidReturns(cfclient.Org{Name: "orphan-org"}, nil)
+		})
+
+		It("omits unbound instances by default", func() {
+			finder := find_bindings.NewBindingFinder(fakeCFClient)
+			bindings, err := finder.FindBindings("p.mysql", find_bindings.FindBindingsOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bindings).To(BeEmpty())
+			Expect(fakeCFClient.GetSpaceByGuidCallCount()).To(Equal(0))
+		})
+
+		It("reports an Orphan binding per unbound instance when IncludeOrphans is set", func() {
+			finder := find_bindings.NewBindingFinder(fakeCFClient)
+			bindings, err := finder.FindBindings("p.mysql", find_bindings.FindBindingsOptions{IncludeOrphans: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bindings).To(Equal([]find_bindings.Binding{
+				{
+					Name:                "",
+					ServiceInstanceName: "orphan-instance",
+					ServiceInstanceGuid: "orphan-instance-guid",
+					OrgName:             "orphan-org",
+					SpaceName:           "orphan-space",
+					Type:                "Orphan",
+				},
+			}))
+		})
+	})
+
+	Context("scoping to an org or space", func() {
+		var fakeCFClient *findbindingsfakes.FakeCFClient
+
+		BeforeEach(func() {
+			fakeCFClient = &findbindingsfakes.FakeCFClient{}
+			fakeCFClient.ListServicesByQueryReturns([]cfclient.Service{{Label: "p.mysql", Guid: "service-guid"}}, nil)
+			fakeCFClient.ListServicePlansByQueryReturns([]cfclient.ServicePlan{
+				{Name: "small", Guid: "small-guid", ServiceGuid: "service-guid"},
+			}, nil)
+
+			fakeCFClient.ListServiceInstancesByQueryReturns([]cfclient.ServiceInstance{
+				{Name: "in-scope", Guid: "in-scope-guid", SpaceGuid: "target-space-guid"},
+				{Name: "out-of-scope", Guid: "out-of-scope-guid", SpaceGuid: "other-space-guid"},
+			}, nil)
+
+			fakeCFClient.ListServiceBindingsByQueryStub = func(query url.Values) ([]cfclient.ServiceBinding, error) {
+				if query.Get("q") == "service_instance_guid:in-scope-guid" {
+					return []cfclient.ServiceBinding{{Guid: "binding-guid", AppGuid: "app-guid", ServiceInstanceGuid: "in-scope-guid"}}, nil
+				}
+				return []cfclient.ServiceBinding{{Guid: "binding-guid-2", AppGuid: "app-guid-2", ServiceInstanceGuid: "out-of-scope-guid"}}, nil
+			}
+			fakeCFClient.ListServiceKeysByQueryReturns(nil, nil)
+			fakeCFClient.GetAppByGuidReturns(cfclient.App{Guid: "app-guid", Name: "app"}, nil)
+
+			fakeCFClient.GetSpaceByGuidStub = func(guid string) (cfclient.Space, error) {
+				if guid == "target-space-guid" {
+					return cfclient.Space{Guid: "target-space-guid", Name: "target-space", OrganizationGuid: "target-org-guid"}, nil
+				}
+				return cfclient.Space{Guid: "other-space-guid", Name: "other-space", OrganizationGuid: "other-org-guid"}, nil
+			}
+			fakeCFClient.GetOrgByGuidStub = func(guid string) (cfclient.Org, error) {
+				if guid == "target-org-guid" {
+					return cfclient.Org{Guid: "target-org-guid", Name: "target-org"}, nil
+				}
+				return cfclient.Org{Guid: "other-org-guid", Name: "other-org"}, nil
+			}
+		})
+
+		It("FindBindingsInSpace only traverses instances in the resolved space", func() {
+			fakeCFClient.GetSpaceByNameReturns(cfclient.Space{Guid: "target-space-guid", Name: "target-space", OrganizationGuid: "target-org-guid"}, nil)
+
+			finder := find_bindings.NewBindingFinder(fakeCFClient)
+			bindings, err := finder.FindBindingsInSpace("p.mysql", "target-org-guid", "target-space", find_bindings.FindBindingsOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			name, orgGuid := fakeCFClient.GetSpaceByNameArgsForCall(0)
+			Expect(name).To(Equal("target-space"))
+			Expect(orgGuid).To(Equal("target-org-guid"))
+
+			Expect(fakeCFClient.ListServiceInstancesByQueryCallCount()).To(Equal(1))
+			Expect(fakeCFClient.ListServiceInstancesByQueryArgsForCall(0).Get("q")).To(Equal("service_plan_guid:small-guid;space_guid:target-space-guid"))
+			Expect(fakeCFClient.ListServiceBindingsByQueryCallCount()).To(Equal(1))
+			Expect(fakeCFClient.ListServiceBindingsByQueryArgsForCall(0).Get("q")).To(Equal("service_instance_guid:in-scope-guid"))
+
+			Expect(bindings).To(Equal([]find_bindings.Binding{
+				{
+					Name:                "app",
+					ServiceInstanceName: "in-scope",
+					ServiceInstanceGuid: "in-scope-guid",
+					OrgName:             "target-org",
+					SpaceName:           "target-space",
+					Type:                "AppBinding",
+				},
+			}))
+		})
+
+		It("FindBindingsInOrg only traverses instances whose space belongs to the resolved org", func() {
+			fakeCFClient.GetOrgByNameReturns(cfclient.Org{Guid: "target-org-guid", Name: "target-org"}, nil)
+
+			finder := find_bindings.NewBindingFinder(fakeCFClient)
+			bindings, err := finder.FindBindingsInOrg("p.mysql", "target-org", find_bindings.FindBindingsOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeCFClient.GetOrgByNameArgsForCall(0)).To(Equal("target-org"))
+			Expect(fakeCFClient.ListServiceInstancesByQueryCallCount()).To(Equal(1))
+
+			var guids []string
+			for _, b := range bindings {
+				guids = append(guids, b.ServiceInstanceGuid)
+			}
+			Expect(guids).To(ConsistOf("in-scope-guid"))
+		})
+
+		It("honors IncludeOrphans when scoped to a space", func() {
+			fakeCFClient.GetSpaceByNameReturns(cfclient.Space{Guid: "target-space-guid", Name: "target-space", OrganizationGuid: "target-org-guid"}, nil)
+			fakeCFClient.ListServiceBindingsByQueryReturns(nil, nil)
+
+			finder := find_bindings.NewBindingFinder(fakeCFClient)
+			bindings, err := finder.FindBindingsInSpace("p.mysql", "target-org-guid", "target-space", find_bindings.FindBindingsOptions{IncludeOrphans: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			var types []string
+			for _, b := range bindings {
+				types = append(types, b.Type)
+			}
+			Expect(types).To(ContainElement("Orphan"))
+		})
+
+		It("honors IncludeOrphans when scoped to an org", func() {
+			fakeCFClient.GetOrgByNameReturns(cfclient.Org{Guid: "target-org-guid", Name: "target-org"}, nil)
+			fakeCFClient.ListServiceBindingsByQueryReturns(nil, nil)
+
+			finder := find_bindings.NewBindingFinder(fakeCFClient)
+			bindings, err := finder.FindBindingsInOrg("p.mysql", "target-org", find_bindings.FindBindingsOptions{IncludeOrphans: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			var types []string
+			for _, b := range bindings {
+				types = append(types, b.Type)
+			}
+			Expect(types).To(ContainElement("Orphan"))
+		})
+	})
 })
\ No newline at end of file
@@ -0,0 +1,103 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package find_bindings
+
+import (
+	"net/url"
+	"strconv"
+
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
+)
+
+// defaultResultsPerPage matches the CF v2 API's default page size. Any list
+// endpoint can return results spread across more than one page once a
+// foundation has this many matching resources, so a full page is always
+// treated as "there may be more" until a short page confirms the list ended.
+const defaultResultsPerPage = 50
+
+// pageQuery returns query unchanged for the first page, since that's the page
+// CF v2 serves by default. Later pages add the "page" parameter CF's list
+// endpoints use in place of following a raw next_url cursor.
+func pageQuery(query url.Values, page int) url.Values {
+	if page <= 1 {
+		return query
+	}
+
+	paged := url.Values{}
+	for k, v := range query {
+		paged[k] = v
+	}
+	paged.Set("page", strconv.Itoa(page))
+
+	return paged
+}
+
+func (bf *BindingFinder) listServiceInstances(query url.Values) ([]cfclient.ServiceInstance, error) {
+	var all []cfclient.ServiceInstance
+	for page := 1; ; page++ {
+		instances, err := bf.cfClient.ListServiceInstancesByQuery(pageQuery(query, page))
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, instances...)
+		if len(instances) < defaultResultsPerPage {
+			return all, nil
+		}
+	}
+}
+
+func (bf *BindingFinder) listServiceBindings(query url.Values) ([]cfclient.ServiceBinding, error) {
+	var all []cfclient.ServiceBinding
+	for page := 1; ; page++ {
+		bindings, err := bf.cfClient.ListServiceBindingsByQuery(pageQuery(query, page))
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, bindings...)
+		if len(bindings) < defaultResultsPerPage {
+			return all, nil
+		}
+	}
+}
+
+func (bf *BindingFinder) listUserProvidedServiceInstances(query url.Values) ([]cfclient.UserProvidedServiceInstance, error) {
+	var all []cfclient.UserProvidedServiceInstance
+	for page := 1; ; page++ {
+		instances, err := bf.cfClient.ListUserProvidedServiceInstancesByQuery(pageQuery(query, page))
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, instances...)
+		if len(instances) < defaultResultsPerPage {
+			return all, nil
+		}
+	}
+}
+
+func (bf *BindingFinder) listServiceKeys(query url.Values) ([]cfclient.ServiceKey, error) {
+	var all []cfclient.ServiceKey
+	for page := 1; ; page++ {
+		keys, err := bf.cfClient.ListServiceKeysByQuery(pageQuery(query, page))
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, keys...)
+		if len(keys) < defaultResultsPerPage {
+			return all, nil
+		}
+	}
+}
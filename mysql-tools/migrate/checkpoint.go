@@ -0,0 +1,273 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Phase names recorded to the checkpoint file as a migration progresses, so a
+// resumed migration knows how far the previous attempt got.
+const (
+	PhaseStarted          = "started"
+	PhaseCreatedRecipient = "created_recipient"
+	PhasePushedApp        = "pushed_app"
+	PhaseBoundDonor       = "bound_donor"
+	PhaseBoundRecipient   = "bound_recipient"
+	PhaseStartedApp       = "started_app"
+	PhaseTaskRunning      = "task_running"
+	PhaseRenamed          = "renamed"
+)
+
+// Persistor is modeled after cloudfoundry-cli's configuration.NewDiskPersistor:
+// a small seam around reading and writing a single JSON-encoded file so it can
+// be swapped out in tests.
+type Persistor interface {
+	Delete()
+	Exists() bool
+	Load(data interface{}) error
+	Save(data interface{}) error
+}
+
+type DiskPersistor struct {
+	path string
+}
+
+func NewDiskPersistor(path string) *DiskPersistor {
+	return &DiskPersistor{path: path}
+}
+
+func (p *DiskPersistor) Exists() bool {
+	_, err := os.Stat(p.path)
+	return err == nil
+}
+
+func (p *DiskPersistor) Delete() {
+	os.Remove(p.path)
+}
+
+func (p *DiskPersistor) Load(data interface{}) error {
+	bytes, err := ioutil.ReadFile(p.path)
+	if err != nil {
+		return errors.Wrapf(err, "error reading checkpoint %s", p.path)
+	}
+
+	return json.Unmarshal(bytes, data)
+}
+
+func (p *DiskPersistor) Save(data interface{}) error {
+	bytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error marshalling checkpoint")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0700); err != nil {
+		return errors.Wrapf(err, "error creating checkpoint directory %s", filepath.Dir(p.path))
+	}
+
+	return ioutil.WriteFile(p.path, bytes, 0600)
+}
+
+// Checkpoint is the state persisted after each phase of a migration, so an
+// operator can resume a failed migration with `cf mysql-tools migrate --resume`.
+type Checkpoint struct {
+	Path                  string        `json:"-"`
+	DonorInstanceName     string        `json:"donor_instance_name"`
+	RecipientInstanceName string        `json:"recipient_instance_name"`
+	PlanType              string        `json:"plan_type"`
+	Mode                  MigrationMode `json:"mode"`
+	IncludeSchema         string        `json:"include_schema"`
+	ExcludeSchema         string        `json:"exclude_schema"`
+	Parallelism           int           `json:"parallelism"`
+	AppName               string        `json:"app_name"`
+	Phase                 string        `json:"phase"`
+	CompletedSchemas      []string      `json:"completed_schemas,omitempty"`
+	StartedAt             time.Time     `json:"started_at"`
+	UpdatedAt             time.Time     `json:"updated_at"`
+}
+
+func DefaultCheckpointDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "error determining home directory")
+	}
+
+	return filepath.Join(home, ".cf", "plugins", "mysql-tools", "migrations"), nil
+}
+
+func DefaultCheckpointPath(donorInstanceName string) (string, error) {
+	dir, err := DefaultCheckpointDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, fmt.Sprintf("%s-%d.json", donorInstanceName, time.Now().Unix())), nil
+}
+
+// ListCheckpoints returns every checkpoint left behind in the default checkpoint
+// directory, for `cf mysql-tools migrations list`.
+func ListCheckpoints() ([]Checkpoint, error) {
+	dir, err := DefaultCheckpointDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "error reading checkpoint directory %s", dir)
+	}
+
+	var checkpoints []Checkpoint
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		checkpoint, err := LoadCheckpoint(path)
+		if err != nil {
+			continue
+		}
+
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+func LoadCheckpoint(path string) (Checkpoint, error) {
+	var checkpoint Checkpoint
+	if err := NewDiskPersistor(path).Load(&checkpoint); err != nil {
+		return Checkpoint{}, err
+	}
+
+	checkpoint.Path = path
+	return checkpoint, nil
+}
+
+func AbortCheckpoint(path string) error {
+	NewDiskPersistor(path).Delete()
+	return nil
+}
+
+// StartCheckpoint begins tracking a new migration's progress on disk, under the
+// default checkpoint directory.
+func (m *Migrator) StartCheckpoint(donorInstanceName, recipientInstanceName, planType string, opts MigrateOptions) error {
+	path, err := DefaultCheckpointPath(donorInstanceName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	m.persistor = NewDiskPersistor(path)
+	m.checkpoint = &Checkpoint{
+		Path:                  path,
+		DonorInstanceName:     donorInstanceName,
+		RecipientInstanceName: recipientInstanceName,
+		PlanType:              planType,
+		Mode:                  opts.Mode,
+		IncludeSchema:         opts.IncludeSchema,
+		ExcludeSchema:         opts.ExcludeSchema,
+		Parallelism:           opts.Parallelism,
+		Phase:                 PhaseStarted,
+		StartedAt:             now,
+		UpdatedAt:             now,
+	}
+
+	return m.saveCheckpoint()
+}
+
+// ResumeCheckpoint loads a previously written checkpoint and arranges for this
+// Migrator to keep updating it as the resumed migration progresses.
+func (m *Migrator) ResumeCheckpoint(path string) (Checkpoint, error) {
+	checkpoint, err := LoadCheckpoint(path)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	m.persistor = NewDiskPersistor(path)
+	m.checkpoint = &checkpoint
+	m.appName = checkpoint.AppName
+
+	return checkpoint, nil
+}
+
+func (m *Migrator) recordPhase(phase string) {
+	if m.checkpoint == nil {
+		return
+	}
+
+	m.checkpoint.Phase = phase
+	m.checkpoint.AppName = m.appName
+	m.checkpoint.UpdatedAt = time.Now()
+	if err := m.saveCheckpoint(); err != nil {
+		log.Printf("Warning: unable to update checkpoint %s: %s", m.checkpoint.Path, err)
+	}
+}
+
+// recordSchemaComplete records that one schema's migration task has finished
+// successfully, so a resumed parallel migration knows to skip it rather than
+// redoing every schema from scratch. Callers running several schemas
+// concurrently must serialize calls to this method themselves.
+func (m *Migrator) recordSchemaComplete(schema string) {
+	if m.checkpoint == nil {
+		return
+	}
+
+	m.checkpoint.CompletedSchemas = append(m.checkpoint.CompletedSchemas, schema)
+	m.recordPhase(fmt.Sprintf("%s:%s", PhaseTaskRunning, schema))
+}
+
+// completedSchemas returns the set of schemas already recorded as migrated in
+// the checkpoint, for filtering out of a resumed parallel migration.
+func (m *Migrator) completedSchemas() map[string]bool {
+	done := map[string]bool{}
+	if m.checkpoint == nil {
+		return done
+	}
+
+	for _, schema := range m.checkpoint.CompletedSchemas {
+		done[schema] = true
+	}
+
+	return done
+}
+
+func (m *Migrator) saveCheckpoint() error {
+	if m.persistor == nil {
+		return nil
+	}
+
+	return m.persistor.Save(m.checkpoint)
+}
+
+// deleteCheckpoint removes the checkpoint file once a migration has finished
+// successfully; there's nothing left to resume.
+func (m *Migrator) deleteCheckpoint() {
+	if m.persistor == nil {
+		return
+	}
+
+	m.persistor.Delete()
+}
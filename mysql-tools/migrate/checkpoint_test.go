@@ -0,0 +1,79 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package migrate_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/pivotal-cf/mysql-cli-plugin/mysql-tools/migrate"
+)
+
+var _ = Describe("Checkpoint persistence", func() {
+	var checkpointPath string
+
+	BeforeEach(func() {
+		tmpDir, err := ioutil.TempDir("", "checkpoint-test")
+		Expect(err).NotTo(HaveOccurred())
+		checkpointPath = filepath.Join(tmpDir, "donor-instance.json")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(checkpointPath))
+	})
+
+	It("round-trips every field through Save and Load", func() {
+		want := Checkpoint{
+			DonorInstanceName:     "donor-instance",
+			RecipientInstanceName: "recipient-instance",
+			PlanType:              "db-small",
+			Mode:                  "logical-dump",
+			IncludeSchema:         "app1,app2",
+			ExcludeSchema:         "app3",
+			Parallelism:           2,
+			AppName:               "migrate-app-12345",
+			Phase:                 PhaseBoundDonor,
+			CompletedSchemas:      []string{"app1"},
+		}
+
+		Expect(NewDiskPersistor(checkpointPath).Save(&want)).To(Succeed())
+
+		got, err := LoadCheckpoint(checkpointPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		want.Path = checkpointPath
+		Expect(got).To(Equal(want))
+	})
+
+	It("resumes a checkpoint written directly to disk, preserving every field", func() {
+		written := Checkpoint{
+			Path:                  checkpointPath,
+			DonorInstanceName:     "donor-instance",
+			RecipientInstanceName: "recipient-instance",
+			Mode:                  "streaming",
+			IncludeSchema:         "app1",
+			Parallelism:           3,
+			Phase:                 PhaseStarted,
+		}
+		Expect(NewDiskPersistor(checkpointPath).Save(&written)).To(Succeed())
+
+		resumer := &Migrator{}
+		resumed, err := resumer.ResumeCheckpoint(checkpointPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resumed).To(Equal(written))
+	})
+})
@@ -0,0 +1,216 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package migrate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+)
+
+//go:generate counterfeiter . client
+type client interface {
+	ServiceExists(serviceName string) bool
+	CreateServiceInstance(planType, instanceName string) error
+	GetHostnames(instanceName string) ([]string, error)
+	UpdateServiceConfig(instanceName string, jsonParams string) error
+	BindService(appName, serviceName string) error
+	DeleteApp(appName string) error
+	DeleteServiceInstance(instanceName string) error
+	DumpLogs(appName string)
+	GetRecentLogs(appName string) (string, error)
+	PushApp(path, appName string) error
+	RenameService(oldName, newName string) error
+	RunTask(appName, command string) error
+	StartApp(appName string) error
+}
+
+type unpacker interface {
+	Unpack(destDir string) error
+}
+
+type MigrationMode string
+
+const (
+	ModeStreaming   MigrationMode = "streaming"
+	ModeLogicalDump MigrationMode = "logical-dump"
+)
+
+// MigrateOptions controls which migration strategy the migration task runs,
+// which schemas it covers, and how many schemas are migrated at once.
+type MigrateOptions struct {
+	Mode          MigrationMode
+	IncludeSchema string
+	ExcludeSchema string
+	Parallelism   int
+}
+
+func NewMigrator(client client, unpacker unpacker) *Migrator {
+	return &Migrator{
+		client:   client,
+		unpacker: unpacker,
+	}
+}
+
+type Migrator struct {
+	appName    string
+	client     client
+	unpacker   unpacker
+	persistor  Persistor
+	checkpoint *Checkpoint
+}
+
+func (m *Migrator) CheckServiceExists(donorInstanceName string) error {
+	if !m.client.ServiceExists(donorInstanceName) {
+		return fmt.Errorf("service instance %s not found", donorInstanceName)
+	}
+
+	return nil
+}
+
+func (m *Migrator) CreateAndConfigureServiceInstance(planType, serviceName string) error {
+	if err := m.client.CreateServiceInstance(planType, serviceName); err != nil {
+		return errors.Wrap(err, "error creating service instance")
+	}
+
+	instanceIP, err := m.client.GetHostnames(serviceName)
+	if err != nil {
+		m.client.DeleteServiceInstance(serviceName)
+		return errors.Wrap(err, "error obtaining hostname for new service instance")
+	}
+
+	if err := m.client.UpdateServiceConfig(serviceName,
+		fmt.Sprintf(`{"enable_tls": ["%s"]}`, instanceIP)); err != nil {
+		return err
+	}
+
+	m.recordPhase(PhaseCreatedRecipient)
+	return nil
+}
+
+func (m *Migrator) MigrateData(donorInstanceName, recipientInstanceName string, opts MigrateOptions, cleanup bool) error {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "migrate_app_")
+	if err != nil {
+		return errors.Wrapf(err, "error creating temp directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	log.Printf("Unpacking assets for migration to %s", tmpDir)
+	if err = m.unpacker.Unpack(tmpDir); err != nil {
+		return errors.Wrap(err, "error extracting migrate assets")
+	}
+
+	log.Print("Started to push app")
+	if m.appName == "" {
+		m.appName = "migrate-app-" + uuid.New()
+	}
+	if err = m.client.PushApp(tmpDir, m.appName); err != nil {
+		return errors.Wrapf(err, "failed to push application %q", m.appName)
+	}
+	defer func() {
+		if cleanup {
+			m.client.DeleteApp(m.appName)
+			log.Print("Cleaning up...")
+		}
+	}()
+	m.recordPhase(PhasePushedApp)
+	log.Print("Successfully pushed app")
+
+	if err = m.client.BindService(m.appName, donorInstanceName); err != nil {
+		return errors.Wrapf(err, "failed to bind-service %q to application %q", donorInstanceName, m.appName)
+	}
+	m.recordPhase(PhaseBoundDonor)
+	log.Print("Successfully bound app to donor instance")
+
+	if err = m.client.BindService(m.appName, recipientInstanceName); err != nil {
+		return errors.Wrapf(err, "failed to bind-service %q to application %q", recipientInstanceName, m.appName)
+	}
+	m.recordPhase(PhaseBoundRecipient)
+	log.Print("Successfully bound app to recipient instance")
+
+	log.Print("Starting migration app")
+	if err = m.client.StartApp(m.appName); err != nil {
+		return errors.Wrapf(err, "failed to start application %q", m.appName)
+	}
+	m.recordPhase(PhaseStartedApp)
+
+	log.Print("Started to run migration task")
+	m.recordPhase(PhaseTaskRunning)
+	if opts.Parallelism > 1 {
+		// No mode restriction needed here: each worker passes its own --schema
+		// to the migration app, and both StreamingMigrator and LogicalDumpMigrator
+		// scope their dump to that single schema rather than the whole donor.
+		log.Printf("Migrating schemas with up to %d running in parallel", opts.Parallelism)
+		if err := m.migrateSchemasInParallel(donorInstanceName, recipientInstanceName, opts); err != nil {
+			log.Printf("Migration failed: %s", err)
+			return err
+		}
+
+		log.Print("Migration completed successfully")
+		return nil
+	}
+
+	command := migrationCommand(donorInstanceName, recipientInstanceName, opts)
+	if err = m.client.RunTask(m.appName, command); err != nil {
+		log.Printf("Migration failed: %s", err)
+		log.Print("Fetching log output...")
+		time.Sleep(5 * time.Second)
+		m.client.DumpLogs(m.appName)
+		return err
+	}
+
+	log.Print("Migration completed successfully")
+
+	return nil
+}
+
+func migrationCommand(donorInstanceName, recipientInstanceName string, opts MigrateOptions) string {
+	mode := opts.Mode
+	if mode == "" {
+		mode = ModeStreaming
+	}
+
+	command := fmt.Sprintf("./migrate --mode=%s %s %s", mode, donorInstanceName, recipientInstanceName)
+	if opts.IncludeSchema != "" {
+		command += fmt.Sprintf(" --include-schema=%s", opts.IncludeSchema)
+	}
+	if opts.ExcludeSchema != "" {
+		command += fmt.Sprintf(" --exclude-schema=%s", opts.ExcludeSchema)
+	}
+
+	return command
+}
+
+func (m *Migrator) RenameServiceInstances(donorInstanceName, recipientInstanceName string) error {
+	newDonorInstanceName := donorInstanceName + "-old"
+	if err := m.client.RenameService(donorInstanceName, newDonorInstanceName); err != nil {
+		return errors.Wrapf(err, "error renaming service instance %s", donorInstanceName)
+	}
+
+	if err := m.client.RenameService(recipientInstanceName, donorInstanceName); err != nil {
+		return errors.Wrapf(err, "error renaming service instance %s", recipientInstanceName)
+	}
+
+	m.recordPhase(PhaseRenamed)
+	m.deleteCheckpoint()
+	return nil
+}
+
+func (m *Migrator) CleanupOnError(recipientServiceInstance string) error {
+	return m.client.DeleteServiceInstance(recipientServiceInstance)
+}
@@ -0,0 +1,242 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	schemaListMarker     = "SCHEMA_LIST:"
+	schemaProgressMarker = "SCHEMA_PROGRESS:"
+)
+
+// SchemaProgress is the last reported state of one schema's migration task, as
+// emitted by the migration app via the SCHEMA_PROGRESS log marker.
+type SchemaProgress struct {
+	Schema  string `json:"schema"`
+	Status  string `json:"status"`
+	Elapsed string `json:"elapsed,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// TaskReporter polls a migration app's task output for per-schema progress, so a
+// parallel migration can render a live status table while tasks are in flight.
+type TaskReporter interface {
+	Poll(appName string) ([]SchemaProgress, error)
+}
+
+// logTaskReporter tails `cf logs --recent` for an app and picks out the latest
+// SCHEMA_PROGRESS line per schema. It's the only TaskReporter implementation
+// this plugin ships, in keeping with how Preflight pulls its report out of logs.
+type logTaskReporter struct {
+	client client
+}
+
+func newLogTaskReporter(client client) *logTaskReporter {
+	return &logTaskReporter{client: client}
+}
+
+func (r *logTaskReporter) Poll(appName string) ([]SchemaProgress, error) {
+	logs, err := r.client.GetRecentLogs(appName)
+	if err != nil {
+		return nil, errors.Wrap(err, "error polling task progress")
+	}
+
+	return parseSchemaProgress(logs), nil
+}
+
+func parseSchemaProgress(logs string) []SchemaProgress {
+	bySchema := map[string]SchemaProgress{}
+	var order []string
+
+	for _, line := range strings.Split(logs, "\n") {
+		idx := strings.Index(line, schemaProgressMarker)
+		if idx == -1 {
+			continue
+		}
+
+		var progress SchemaProgress
+		if err := json.Unmarshal([]byte(line[idx+len(schemaProgressMarker):]), &progress); err != nil {
+			continue
+		}
+
+		if _, seen := bySchema[progress.Schema]; !seen {
+			order = append(order, progress.Schema)
+		}
+		bySchema[progress.Schema] = progress
+	}
+
+	progress := make([]SchemaProgress, 0, len(order))
+	for _, schema := range order {
+		progress = append(progress, bySchema[schema])
+	}
+
+	return progress
+}
+
+// listSchemas runs the migration app's --list-schemas task and parses the
+// SCHEMA_LIST it prints to the app's logs.
+func (m *Migrator) listSchemas(donorInstanceName, recipientInstanceName string, opts MigrateOptions) ([]string, error) {
+	command := fmt.Sprintf("./migrate --list-schemas %s %s", donorInstanceName, recipientInstanceName)
+	if opts.IncludeSchema != "" {
+		command += fmt.Sprintf(" --include-schema=%s", opts.IncludeSchema)
+	}
+	if opts.ExcludeSchema != "" {
+		command += fmt.Sprintf(" --exclude-schema=%s", opts.ExcludeSchema)
+	}
+
+	if err := m.client.RunTask(m.appName, command); err != nil {
+		return nil, errors.Wrap(err, "error listing donor schemas")
+	}
+
+	logs, err := m.client.GetRecentLogs(m.appName)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching schema list")
+	}
+
+	for _, line := range strings.Split(logs, "\n") {
+		idx := strings.Index(line, schemaListMarker)
+		if idx == -1 {
+			continue
+		}
+
+		var schemas []string
+		if err := json.Unmarshal([]byte(line[idx+len(schemaListMarker):]), &schemas); err != nil {
+			return nil, errors.Wrap(err, "error parsing schema list")
+		}
+
+		return schemas, nil
+	}
+
+	return nil, errors.New("migration app did not report a schema list")
+}
+
+// migrateSchemasInParallel runs one migration task per schema against the
+// already-pushed and bound migration app, using a worker pool bounded by
+// opts.Parallelism. A failure in one schema does not stop its peers; every
+// failed schema is collected and reported once all schemas have finished.
+// Schemas already recorded as migrated in the checkpoint (a resumed run) are
+// skipped rather than redone.
+func (m *Migrator) migrateSchemasInParallel(donorInstanceName, recipientInstanceName string, opts MigrateOptions) error {
+	schemas, err := m.listSchemas(donorInstanceName, recipientInstanceName, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(schemas) == 0 {
+		return errors.New("no schemas matched the configured schema filter")
+	}
+
+	completed := m.completedSchemas()
+	var pending []string
+	for _, schema := range schemas {
+		if !completed[schema] {
+			pending = append(pending, schema)
+		}
+	}
+	if len(pending) == 0 {
+		log.Print("All schemas already migrated per checkpoint; nothing to do")
+		return nil
+	}
+	schemas = pending
+
+	reporter := newLogTaskReporter(m.client)
+	done := make(chan struct{})
+	go m.reportProgress(reporter, done)
+	defer close(done)
+
+	schemaCh := make(chan string, len(schemas))
+	for _, schema := range schemas {
+		schemaCh <- schema
+	}
+	close(schemaCh)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		failed  []string
+		workers = opts.Parallelism
+	)
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(schemas) {
+		workers = len(schemas)
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for schema := range schemaCh {
+				command := migrationCommand(donorInstanceName, recipientInstanceName, opts) + fmt.Sprintf(" --schema=%s", schema)
+				if err := m.client.RunTask(m.appName, command); err != nil {
+					mu.Lock()
+					failed = append(failed, schema)
+					mu.Unlock()
+					continue
+				}
+
+				mu.Lock()
+				m.recordSchemaComplete(schema)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return errors.Errorf("migration failed for schema(s): %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// reportProgress polls the TaskReporter every few seconds and renders a table of
+// per-schema status to stdout, until the done channel is closed.
+func (m *Migrator) reportProgress(reporter TaskReporter, done <-chan struct{}) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			progress, err := reporter.Poll(m.appName)
+			if err != nil || len(progress) == 0 {
+				continue
+			}
+			printSchemaProgress(progress)
+		case <-done:
+			return
+		}
+	}
+}
+
+func printSchemaProgress(progress []SchemaProgress) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SCHEMA\tSTATUS\tELAPSED\tERROR")
+	for _, p := range progress {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Schema, p.Status, p.Elapsed, p.Error)
+	}
+	w.Flush()
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// stubParallelClient is a hand-rolled client fake for exercising
+// migrateSchemasInParallel without a real CF API; no counterfeiter fakes exist
+// for this interface, so it's stubbed directly the way plugin_test.go stubs
+// plugin.Migrator.
+type stubParallelClient struct {
+	client
+
+	schemas    []string
+	failSchema map[string]bool
+	runTask    func(appName, command string) error
+}
+
+func (s *stubParallelClient) RunTask(appName, command string) error {
+	if strings.Contains(command, "--list-schemas") {
+		return nil
+	}
+
+	if s.runTask != nil {
+		return s.runTask(appName, command)
+	}
+
+	for schema := range s.failSchema {
+		if strings.Contains(command, fmt.Sprintf("--schema=%s", schema)) {
+			return fmt.Errorf("task failed for schema %s", schema)
+		}
+	}
+
+	return nil
+}
+
+func (s *stubParallelClient) GetRecentLogs(appName string) (string, error) {
+	schemas, err := json.Marshal(s.schemas)
+	if err != nil {
+		return "", err
+	}
+
+	return schemaListMarker + string(schemas), nil
+}
+
+var _ = Describe("migrateSchemasInParallel", func() {
+	var m *Migrator
+
+	It("never runs more schema tasks concurrently than opts.Parallelism", func() {
+		var inFlight, maxInFlight int32
+
+		stub := &stubParallelClient{
+			schemas: []string{"app1", "app2", "app3", "app4", "app5"},
+			runTask: func(appName, command string) error {
+				current := atomic.AddInt32(&inFlight, 1)
+				for {
+					observed := atomic.LoadInt32(&maxInFlight)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				return nil
+			},
+		}
+		m = NewMigrator(stub, nil)
+
+		err := m.migrateSchemasInParallel("donor", "recipient", MigrateOptions{Parallelism: 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(atomic.LoadInt32(&maxInFlight)).To(BeNumerically("<=", 2))
+	})
+
+	It("keeps migrating the remaining schemas when one schema's task fails", func() {
+		var (
+			mu  sync.Mutex
+			ran []string
+		)
+		stub := &stubParallelClient{
+			schemas: []string{"app1", "app2", "app3"},
+			runTask: func(appName, command string) error {
+				mu.Lock()
+				ran = append(ran, command[strings.Index(command, "--schema=")+len("--schema="):])
+				mu.Unlock()
+				if strings.Contains(command, "--schema=app2") {
+					return fmt.Errorf("task failed for schema app2")
+				}
+				return nil
+			},
+		}
+		m = NewMigrator(stub, nil)
+
+		err := m.migrateSchemasInParallel("donor", "recipient", MigrateOptions{Parallelism: 3})
+		Expect(err).To(MatchError("migration failed for schema(s): app2"))
+		Expect(ran).To(ConsistOf("app1", "app2", "app3"))
+	})
+
+	It("reports every failed schema, not just the first", func() {
+		stub := &stubParallelClient{
+			schemas:    []string{"app1", "app2", "app3"},
+			failSchema: map[string]bool{"app1": true, "app3": true},
+		}
+		m = NewMigrator(stub, nil)
+
+		err := m.migrateSchemasInParallel("donor", "recipient", MigrateOptions{Parallelism: 1})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("app1"))
+		Expect(err.Error()).To(ContainSubstring("app3"))
+	})
+})
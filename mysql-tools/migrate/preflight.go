@@ -0,0 +1,116 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+)
+
+// reportMarker prefixes the JSON-encoded PreflightReport in the inspector app's
+// log output so Preflight can pick it out from the rest of the task's logging.
+const reportMarker = "PREFLIGHT_REPORT:"
+
+type PreflightSeverity string
+
+const (
+	SeverityError   PreflightSeverity = "ERROR"
+	SeverityWarning PreflightSeverity = "WARNING"
+)
+
+type PreflightFinding struct {
+	Severity PreflightSeverity
+	Category string
+	Message  string
+}
+
+type PreflightReport struct {
+	Findings []PreflightFinding
+}
+
+func (r PreflightReport) HasErrors() bool {
+	for _, finding := range r.Findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Migrator) Preflight(donorInstanceName, planType string) (PreflightReport, error) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "preflight_app_")
+	if err != nil {
+		return PreflightReport{}, errors.Wrap(err, "error creating temp directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	log.Print("Unpacking assets for preflight check")
+	if err = m.unpacker.Unpack(tmpDir); err != nil {
+		return PreflightReport{}, errors.Wrap(err, "error extracting migrate assets")
+	}
+
+	appName := "preflight-app-" + uuid.New()
+	log.Print("Pushing preflight inspector app")
+	if err = m.client.PushApp(tmpDir, appName); err != nil {
+		return PreflightReport{}, errors.Wrapf(err, "failed to push application %q", appName)
+	}
+	defer m.client.DeleteApp(appName)
+
+	if err = m.client.BindService(appName, donorInstanceName); err != nil {
+		return PreflightReport{}, errors.Wrapf(err, "failed to bind-service %q to application %q", donorInstanceName, appName)
+	}
+
+	if err = m.client.StartApp(appName); err != nil {
+		return PreflightReport{}, errors.Wrapf(err, "failed to start application %q", appName)
+	}
+
+	command := fmt.Sprintf("./migrate --preflight --plan=%s %s", planType, donorInstanceName)
+	if err = m.client.RunTask(appName, command); err != nil {
+		log.Printf("Preflight task reported a problem: %s", err)
+	}
+
+	logs, err := m.client.GetRecentLogs(appName)
+	if err != nil {
+		return PreflightReport{}, errors.Wrap(err, "error fetching preflight report")
+	}
+
+	return parsePreflightReport(logs)
+}
+
+func parsePreflightReport(logs string) (PreflightReport, error) {
+	var reportLine string
+	for _, line := range strings.Split(logs, "\n") {
+		if idx := strings.Index(line, reportMarker); idx != -1 {
+			reportLine = line[idx+len(reportMarker):]
+		}
+	}
+
+	if reportLine == "" {
+		return PreflightReport{}, errors.New("preflight inspector app did not produce a report")
+	}
+
+	var report PreflightReport
+	if err := json.Unmarshal([]byte(reportLine), &report); err != nil {
+		return PreflightReport{}, errors.Wrap(err, "error parsing preflight report")
+	}
+
+	return report, nil
+}
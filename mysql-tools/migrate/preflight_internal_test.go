@@ -0,0 +1,51 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package migrate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parsePreflightReport", func() {
+	It("extracts the report JSON from the last marker line in the logs", func() {
+		logs := "Some app log noise\n" +
+			`PREFLIGHT_REPORT:{"Findings":[{"Severity":"ERROR","Category":"engine","Message":"nope"}]}` + "\n" +
+			"More trailing log noise\n"
+
+		report, err := parsePreflightReport(logs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Findings).To(Equal([]PreflightFinding{
+			{Severity: SeverityError, Category: "engine", Message: "nope"},
+		}))
+	})
+
+	It("uses the last marker line when more than one is present", func() {
+		logs := `PREFLIGHT_REPORT:{"Findings":[{"Severity":"WARNING","Category":"stale","Message":"ignore me"}]}` + "\n" +
+			`PREFLIGHT_REPORT:{"Findings":[]}`
+
+		report, err := parsePreflightReport(logs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Findings).To(BeEmpty())
+	})
+
+	It("errors when no marker line is present", func() {
+		_, err := parsePreflightReport("nothing interesting here")
+		Expect(err).To(MatchError(ContainSubstring("did not produce a report")))
+	})
+
+	It("errors when the marker line isn't valid JSON", func() {
+		_, err := parsePreflightReport("PREFLIGHT_REPORT:{not json")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,56 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package migrate_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/pivotal-cf/mysql-cli-plugin/mysql-tools/migrate"
+)
+
+func TestMigrate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Migrate Suite")
+}
+
+var _ = Describe("PreflightReport", func() {
+	Describe("HasErrors", func() {
+		It("returns true when any finding is an ERROR", func() {
+			report := PreflightReport{
+				Findings: []PreflightFinding{
+					{Severity: SeverityWarning, Category: "charset", Message: "check this"},
+					{Severity: SeverityError, Category: "engine", Message: "fix this"},
+				},
+			}
+
+			Expect(report.HasErrors()).To(BeTrue())
+		})
+
+		It("returns false when every finding is a WARNING", func() {
+			report := PreflightReport{
+				Findings: []PreflightFinding{
+					{Severity: SeverityWarning, Category: "charset", Message: "check this"},
+				},
+			}
+
+			Expect(report.HasErrors()).To(BeFalse())
+		})
+
+		It("returns false with no findings at all", func() {
+			Expect(PreflightReport{}.HasErrors()).To(BeFalse())
+		})
+	})
+})
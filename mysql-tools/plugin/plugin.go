@@ -13,17 +13,21 @@
 package plugin
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
-	"net/url"
 	"os"
+	"regexp"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"code.cloudfoundry.org/cli/plugin"
 	"github.com/blang/semver"
-	"github.com/cloudfoundry-community/go-cfclient"
+	cfclient "github.com/cloudfoundry-community/go-cfclient"
 	"github.com/jessevdk/go-flags"
 	"github.com/pivotal-cf/mysql-cli-plugin/mysql-tools/cf"
+	findbindings "github.com/pivotal-cf/mysql-cli-plugin/mysql-tools/find-bindings"
 	"github.com/pivotal-cf/mysql-cli-plugin/mysql-tools/migrate"
 	"github.com/pivotal-cf/mysql-cli-plugin/mysql-tools/unpack"
 	"github.com/pkg/errors"
@@ -35,18 +39,29 @@ var (
 )
 
 const (
-	usage = `cf mysql-tools migrate [-h] [--no-cleanup] <source-service-instance> <p.mysql-plan-type>
+	usage = `cf mysql-tools migrate [-h] [--no-cleanup] [--mode=streaming|logical-dump] [--force] [--parallelism=N] <source-service-instance> <p.mysql-plan-type>
+   cf mysql-tools migrate --resume <checkpoint-file>
+   cf mysql-tools migrations list|show|abort [<checkpoint-file>]
+   cf mysql-tools preflight [-h] <source-service-instance> <p.mysql-plan-type>
+   cf mysql-tools find-bindings [-h] [-o table|json] [--match-user-provided=REGEXP] [--include-orphans] [--org=ORG] [--space=SPACE] <service-label>
    cf mysql-tools version`
-	migrateUsage = `cf mysql-tools migrate [-h] [--no-cleanup] <source-service-instance> <p.mysql-plan-type>`
+	migrateUsage      = `cf mysql-tools migrate [-h] [--no-cleanup] [--mode=streaming|logical-dump] [--force] [--parallelism=N] <source-service-instance> <p.mysql-plan-type>
+   cf mysql-tools migrate --resume <checkpoint-file>`
+	preflightUsage    = `cf mysql-tools preflight [-h] <source-service-instance> <p.mysql-plan-type>`
+	findBindingsUsage = `cf mysql-tools find-bindings [-h] [-o table|json] [--match-user-provided=REGEXP] [--include-orphans] [--org=ORG] [--space=SPACE] <service-label>`
+	migrationsUsage   = `cf mysql-tools migrations list|show|abort [<checkpoint-file>]`
 )
 
 //go:generate counterfeiter . Migrator
 type Migrator interface {
 	CheckServiceExists(donorInstanceName string) error
 	CreateAndConfigureServiceInstance(planType, serviceName string) error
-	MigrateData(donorInstanceName, recipientInstanceName string, cleanup bool) error
+	MigrateData(donorInstanceName, recipientInstanceName string, opts migrate.MigrateOptions, cleanup bool) error
 	RenameServiceInstances(donorInstanceName, recipientInstanceName string) error
 	CleanupOnError(recipientInstanceName string) error
+	Preflight(donorInstanceName, planType string) (migrate.PreflightReport, error)
+	StartCheckpoint(donorInstanceName, recipientInstanceName, planType string, opts migrate.MigrateOptions) error
+	ResumeCheckpoint(path string) (migrate.Checkpoint, error)
 }
 
 type MySQLPlugin struct {
@@ -76,7 +91,8 @@ USAGE:
 	}
 
 	command := args[1]
-	migrator := migrate.NewMigrator(cf.NewClient(cliConnection), unpack.NewUnpacker())
+	cfClient := cf.NewClient(cliConnection)
+	migrator := migrate.NewMigrator(cfClient, unpack.NewUnpacker())
 
 	switch command {
 	default:
@@ -84,7 +100,12 @@ USAGE:
 	case "version":
 		fmt.Printf("%s (%s)\n", version, gitSHA)
 		os.Exit(0)
-	// case "find-bindings":
+	case "find-bindings":
+		c.err = FindBindings(cfClient, args[2:])
+	case "preflight":
+		c.err = Preflight(migrator, args[2:])
+	case "migrations":
+		c.err = Migrations(args[2:])
 	case "migrate":
 		c.err = Migrate(migrator, args[2:])
 	}
@@ -116,8 +137,14 @@ func Migrate(migrator Migrator, args []string) error {
 		Args struct {
 			Source   string `positional-arg-name:"<source-service-instance>"`
 			PlanName string `positional-arg-name:"<p.mysql-plan-type>"`
-		} `positional-args:"yes" required:"yes"`
-		NoCleanup bool `long:"no-cleanup" description:"don't clean up migration app and new service instance after a failed migration'"`
+		} `positional-args:"yes"`
+		NoCleanup     bool   `long:"no-cleanup" description:"don't clean up migration app and new service instance after a failed migration'"`
+		Mode          string `long:"mode" description:"migration strategy to use" default:"streaming" choice:"streaming" choice:"logical-dump"`
+		IncludeSchema string `long:"include-schema" description:"comma-separated list of schemas to migrate (default: all user schemas)"`
+		ExcludeSchema string `long:"exclude-schema" description:"comma-separated list of schemas to exclude from migration"`
+		Force         bool   `long:"force" description:"proceed with migration even if the preflight check reports errors"`
+		Resume        string `long:"resume" description:"resume a previously interrupted migration from a checkpoint file"`
+		Parallelism   int    `long:"parallelism" description:"number of schemas to migrate concurrently" default:"1"`
 	}
 
 	parser := flags.NewParser(&opts, flags.None)
@@ -131,21 +158,53 @@ func Migrate(migrator Migrator, args []string) error {
 		}
 		return errors.Errorf("Usage: %s\n\n%s", migrateUsage, msg)
 	}
+
+	if opts.Resume != "" {
+		return resumeMigration(migrator, opts.Resume, !opts.NoCleanup)
+	}
+
+	if opts.Args.Source == "" || opts.Args.PlanName == "" {
+		return errors.Errorf("Usage: %s\n\nthe required arguments `<source-service-instance>` and `<p.mysql-plan-type>` were not provided", migrateUsage)
+	}
+
 	donorInstanceName := opts.Args.Source
 	tempRecipientInstanceName := donorInstanceName + "-new"
 	destPlan := opts.Args.PlanName
 	cleanup := !opts.NoCleanup
+	migrateOpts := migrate.MigrateOptions{
+		Mode:          migrate.MigrationMode(opts.Mode),
+		IncludeSchema: opts.IncludeSchema,
+		ExcludeSchema: opts.ExcludeSchema,
+		Parallelism:   opts.Parallelism,
+	}
 
 	if err := migrator.CheckServiceExists(donorInstanceName); err != nil {
 		return err
 	}
 
-	log.Printf("Warning: The mysql-tools migrate command will not migrate any triggers, routines or events.")
+	log.Printf("Running preflight check against %q", donorInstanceName)
+	report, err := migrator.Preflight(donorInstanceName, destPlan)
+	if err != nil {
+		return errors.Wrap(err, "error running preflight check")
+	}
+
+	printPreflightReport(report)
+	if report.HasErrors() && !opts.Force {
+		return errors.New("preflight check reported errors; pass --force to migrate anyway")
+	}
+
+	if migrateOpts.Mode != migrate.ModeLogicalDump {
+		log.Printf("Warning: The mysql-tools migrate command will not migrate any triggers, routines or events. Use --mode=logical-dump to migrate them.")
+	}
 	productName := os.Getenv("RECIPIENT_PRODUCT_NAME")
 	if productName == "" {
 		productName = "p.mysql"
 	}
 
+	if err := migrator.StartCheckpoint(donorInstanceName, tempRecipientInstanceName, destPlan, migrateOpts); err != nil {
+		log.Printf("Warning: unable to create migration checkpoint: %s", err)
+	}
+
 	log.Printf("Creating new service instance %q for service %s using plan %s", tempRecipientInstanceName, productName, destPlan)
 	if err := migrator.CreateAndConfigureServiceInstance(destPlan, tempRecipientInstanceName); err != nil {
 		if cleanup {
@@ -162,7 +221,7 @@ func Migrate(migrator Migrator, args []string) error {
 		)
 	}
 
-	if err := migrator.MigrateData(donorInstanceName, tempRecipientInstanceName, cleanup); err != nil {
+	if err := migrator.MigrateData(donorInstanceName, tempRecipientInstanceName, migrateOpts, cleanup); err != nil {
 		if cleanup {
 			migrator.CleanupOnError(tempRecipientInstanceName)
 
@@ -181,53 +240,273 @@ func Migrate(migrator Migrator, args []string) error {
 	return migrator.RenameServiceInstances(donorInstanceName, tempRecipientInstanceName)
 }
 
-//go:generate counterfeiter . CFClient
-type CFClient interface {
-	ListServicesByQuery(url.Values) ([]cfclient.Service, error)
-}
-
-type ServiceBinding struct {
-	App   string
-	Key   string
-	Org   string
-	Space string
-}
-
-type bindingFinder struct {
-	cfClient CFClient
-}
-
-func NewBindingFinder(cfClient CFClient) *bindingFinder {
-	return &bindingFinder{
-		cfClient: cfClient,
-	}
-}
-
-func (bf *bindingFinder) FindBindings(serviceLabel string) ([]ServiceBinding, error) {
-	//cf curl " /v2/services?q=label:p.mysql"
-	//	get resources entity.service_plans_url "/v2/services/9cbbd018-236f-4171-8585-594ebfde52f2/service_plans"
-	//	cf curl service_plans_url
-	//		get resources entity.service_instances_url "/v2/spaces/8b892a65-bf0e-4276-ad47-30757c4f2251/service_instances"
-	//		cf curl service_instances_url
-	//			get resources entity.service_bindings_url "/v2/service_instances/00d4ce31-bbbe-48f6-b15d-fcbd3380f50a/service_bindings"
-	//			get resources entity.service_keys_url "/v2/service_instances/00d4ce31-bbbe-48f6-b15d-fcbd3380f50a/service_keys"
-	//			cf curl service_bindings_url
-	//				get resources entity.app_guid
-	//			cf curl service_keys_url
-	//				get resources entity.name    ?
-	//				get resources metadata.guid  ?
-	//			get resources entity.space_url "/v2/spaces/8b892a65-bf0e-4276-ad47-30757c4f2251"
-	//			cf curl space_url
-	//				get resources entity.name
-	//				get resources entity.organization_url "/v2/organizations/10b9207b-1c15-46d8-9946-a2374b8c40e5"
-	//				cf curl organization_url
-	//					get resources entity.name
-	u := url.Values{}
-	u.Set("q", fmt.Sprintf("label:%s", serviceLabel))
-	services, _ := bf.cfClient.ListServicesByQuery(u)
-	return []ServiceBinding{
-		{App: "app", Key: "", Org: "org-name", Space: "space-name"},
-	}, nil
+// resumeMigration picks up a previously interrupted migration from its checkpoint
+// file. It re-creates the recipient instance only if the checkpoint is still in
+// the started phase and returns at once if the rename already happened; in every
+// other phase it reruns MigrateData from the app push onward, reusing the
+// checkpointed app name and skipping schemas already recorded as completed.
+func resumeMigration(migrator Migrator, checkpointPath string, cleanup bool) error {
+	checkpoint, err := migrator.ResumeCheckpoint(checkpointPath)
+	if err != nil {
+		return errors.Wrap(err, "error loading checkpoint")
+	}
+
+	log.Printf("Resuming migration of %q from phase %q", checkpoint.DonorInstanceName, checkpoint.Phase)
+	donorInstanceName := checkpoint.DonorInstanceName
+	recipientInstanceName := checkpoint.RecipientInstanceName
+	migrateOpts := migrate.MigrateOptions{
+		Mode:          checkpoint.Mode,
+		IncludeSchema: checkpoint.IncludeSchema,
+		ExcludeSchema: checkpoint.ExcludeSchema,
+		Parallelism:   checkpoint.Parallelism,
+	}
+
+	if checkpoint.Phase == migrate.PhaseStarted {
+		if err := migrator.CreateAndConfigureServiceInstance(checkpoint.PlanType, recipientInstanceName); err != nil {
+			if cleanup {
+				migrator.CleanupOnError(recipientInstanceName)
+			}
+			return errors.Wrap(err, "error creating service instance")
+		}
+	}
+
+	if checkpoint.Phase == migrate.PhaseRenamed {
+		return nil
+	}
+
+	if err := migrator.MigrateData(donorInstanceName, recipientInstanceName, migrateOpts, cleanup); err != nil {
+		if cleanup {
+			migrator.CleanupOnError(recipientInstanceName)
+		}
+		return errors.Wrap(err, "error migrating data")
+	}
+
+	return migrator.RenameServiceInstances(donorInstanceName, recipientInstanceName)
+}
+
+// Migrations implements `cf mysql-tools migrations list|show|abort`, letting an
+// operator inspect or discard checkpoints left behind by interrupted migrations.
+func Migrations(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("Usage: %s\n\nexpected a subcommand: list, show, or abort", migrationsUsage)
+	}
+
+	subcommand, rest := args[0], args[1:]
+	switch subcommand {
+	case "list":
+		return migrationsList()
+	case "show":
+		return migrationsShow(rest)
+	case "abort":
+		return migrationsAbort(rest)
+	default:
+		return errors.Errorf("Usage: %s\n\nunknown subcommand %q", migrationsUsage, subcommand)
+	}
+}
+
+func migrationsList() error {
+	checkpoints, err := migrate.ListCheckpoints()
+	if err != nil {
+		return errors.Wrap(err, "error listing migrations")
+	}
+
+	if len(checkpoints) == 0 {
+		fmt.Println("No in-progress migrations found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DONOR\tRECIPIENT\tPHASE\tUPDATED\tCHECKPOINT")
+	for _, checkpoint := range checkpoints {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			checkpoint.DonorInstanceName,
+			checkpoint.RecipientInstanceName,
+			checkpoint.Phase,
+			checkpoint.UpdatedAt.Format(time.RFC3339),
+			checkpoint.Path,
+		)
+	}
+	w.Flush()
+
+	return nil
+}
+
+func migrationsShow(args []string) error {
+	if len(args) != 1 {
+		return errors.Errorf("Usage: %s\n\nshow requires a checkpoint file", migrationsUsage)
+	}
+
+	checkpoint, err := migrate.LoadCheckpoint(args[0])
+	if err != nil {
+		return errors.Wrap(err, "error loading checkpoint")
+	}
+
+	out, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error marshalling checkpoint")
+	}
+
+	fmt.Println(string(out))
+	return nil
+}
+
+func migrationsAbort(args []string) error {
+	if len(args) != 1 {
+		return errors.Errorf("Usage: %s\n\nabort requires a checkpoint file", migrationsUsage)
+	}
+
+	if err := migrate.AbortCheckpoint(args[0]); err != nil {
+		return errors.Wrap(err, "error aborting migration")
+	}
+
+	fmt.Printf("Aborted migration checkpoint %s\n", args[0])
+	return nil
+}
+
+func Preflight(migrator Migrator, args []string) error {
+	var opts struct {
+		Args struct {
+			Source   string `positional-arg-name:"<source-service-instance>"`
+			PlanName string `positional-arg-name:"<p.mysql-plan-type>"`
+		} `positional-args:"yes" required:"yes"`
+	}
+
+	parser := flags.NewParser(&opts, flags.None)
+	parser.Name = "cf mysql-tools preflight"
+	args, err := parser.ParseArgs(args)
+	if err != nil || len(args) != 0 {
+		msg := fmt.Sprintf("unexpected arguments: %s", strings.Join(args, " "))
+		if err != nil {
+			msg = err.Error()
+		}
+		return errors.Errorf("Usage: %s\n\n%s", preflightUsage, msg)
+	}
+
+	report, err := migrator.Preflight(opts.Args.Source, opts.Args.PlanName)
+	if err != nil {
+		return errors.Wrap(err, "error running preflight check")
+	}
+
+	printPreflightReport(report)
+	if report.HasErrors() {
+		return errors.New("preflight check reported errors")
+	}
+
+	return nil
+}
+
+func printPreflightReport(report migrate.PreflightReport) {
+	if len(report.Findings) == 0 {
+		fmt.Println("Preflight check passed with no findings.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SEVERITY\tCATEGORY\tMESSAGE")
+	for _, finding := range report.Findings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", finding.Severity, finding.Category, finding.Message)
+	}
+	w.Flush()
+}
+
+func FindBindings(cfClient findbindings.CFClient, args []string) error {
+	var opts struct {
+		Args struct {
+			ServiceLabel string `positional-arg-name:"<service-label>"`
+		} `positional-args:"yes" required:"yes"`
+		Output            string `short:"o" long:"output" description:"output format: table or json" default:"table" choice:"table" choice:"json"`
+		MatchUserProvided string `long:"match-user-provided" description:"also include user-provided service instances whose hostname, host or uri credential matches this regular expression"`
+		IncludeOrphans    bool   `long:"include-orphans" description:"also include service instances that have no bound apps or service keys"`
+		Org               string `long:"org" description:"limit results to a single org"`
+		Space             string `long:"space" description:"limit results to a single space within --org"`
+	}
+
+	parser := flags.NewParser(&opts, flags.None)
+	parser.Name = "cf mysql-tools find-bindings"
+	args, err := parser.ParseArgs(args)
+	if err != nil || len(args) != 0 {
+		msg := fmt.Sprintf("unexpected arguments: %s", strings.Join(args, " "))
+		if err != nil {
+			msg = err.Error()
+		}
+		return errors.Errorf("Usage: %s\n\n%s", findBindingsUsage, msg)
+	}
+
+	var finderOpts []findbindings.Option
+	if opts.MatchUserProvided != "" {
+		pattern, err := regexp.Compile(opts.MatchUserProvided)
+		if err != nil {
+			return errors.Wrap(err, "invalid --match-user-provided pattern")
+		}
+
+		finderOpts = append(finderOpts, findbindings.WithUserProvidedMatcher(func(upsi cfclient.UserProvidedServiceInstance) bool {
+			for _, key := range []string{"hostname", "host", "uri"} {
+				if value, ok := upsi.Credentials[key].(string); ok && pattern.MatchString(value) {
+					return true
+				}
+			}
+			return false
+		}))
+	}
+
+	if opts.Space != "" && opts.Org == "" {
+		return errors.New("--space requires --org")
+	}
+
+	finder := findbindings.NewBindingFinder(cfClient, finderOpts...)
+
+	findOpts := findbindings.FindBindingsOptions{
+		IncludeOrphans: opts.IncludeOrphans,
+	}
+
+	var bindings []findbindings.Binding
+	switch {
+	case opts.Space != "":
+		org, err := cfClient.GetOrgByName(opts.Org)
+		if err != nil {
+			return errors.Wrapf(err, "error looking up org %q", opts.Org)
+		}
+		bindings, err = finder.FindBindingsInSpace(opts.Args.ServiceLabel, org.Guid, opts.Space, findOpts)
+		if err != nil {
+			return errors.Wrap(err, "error finding bindings")
+		}
+	case opts.Org != "":
+		bindings, err = finder.FindBindingsInOrg(opts.Args.ServiceLabel, opts.Org, findOpts)
+		if err != nil {
+			return errors.Wrap(err, "error finding bindings")
+		}
+	default:
+		bindings, err = finder.FindBindings(opts.Args.ServiceLabel, findOpts)
+		if err != nil {
+			return errors.Wrap(err, "error finding bindings")
+		}
+	}
+
+	if opts.Output == "json" {
+		return printBindingsJSON(bindings)
+	}
+
+	printBindingsTable(bindings)
+	return nil
+}
+
+func printBindingsJSON(bindings []findbindings.Binding) error {
+	out, err := json.MarshalIndent(bindings, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error marshalling bindings")
+	}
+
+	fmt.Println(string(out))
+	return nil
+}
+
+func printBindingsTable(bindings []findbindings.Binding) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tNAME\tSERVICE INSTANCE\tORG\tSPACE")
+	for _, b := range bindings {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", b.Type, b.Name, b.ServiceInstanceName, b.OrgName, b.SpaceName)
+	}
+	w.Flush()
 }
 
 func versionFromSemver(in string) plugin.VersionType {
@@ -0,0 +1,231 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package plugin_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/mysql-cli-plugin/mysql-tools/find-bindings/find-bindingsfakes"
+	"github.com/pivotal-cf/mysql-cli-plugin/mysql-tools/migrate"
+	"github.com/pivotal-cf/mysql-cli-plugin/mysql-tools/plugin"
+)
+
+// captureStdout runs fn with os.Stdout redirected to a pipe, and returns
+// whatever it wrote, for asserting on Migrations' printed output.
+func captureStdout(fn func()) string {
+	r, w, err := os.Pipe()
+	Expect(err).NotTo(HaveOccurred())
+
+	original := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = original }()
+
+	fn()
+
+	Expect(w.Close()).To(Succeed())
+	out, err := ioutil.ReadAll(r)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(out)
+}
+
+func TestPlugin(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Plugin Suite")
+}
+
+// stubMigrator is a hand-rolled plugin.Migrator for exercising the flag
+// parsing and dispatch in this package without standing up a real migration.
+type stubMigrator struct {
+	preflightReport     migrate.PreflightReport
+	preflightErr        error
+	resumeCheckpoint    migrate.Checkpoint
+	resumeCheckpointErr error
+
+	createInstanceCalls int
+	migrateDataCalls    int
+	renameCalls         int
+}
+
+func (s *stubMigrator) CheckServiceExists(string) error { return nil }
+
+func (s *stubMigrator) CreateAndConfigureServiceInstance(string, string) error {
+	s.createInstanceCalls++
+	return nil
+}
+
+func (s *stubMigrator) MigrateData(string, string, migrate.MigrateOptions, bool) error {
+	s.migrateDataCalls++
+	return nil
+}
+
+func (s *stubMigrator) RenameServiceInstances(string, string) error {
+	s.renameCalls++
+	return nil
+}
+
+func (s *stubMigrator) CleanupOnError(string) error { return nil }
+
+func (s *stubMigrator) Preflight(string, string) (migrate.PreflightReport, error) {
+	return s.preflightReport, s.preflightErr
+}
+
+func (s *stubMigrator) StartCheckpoint(string, string, string, migrate.MigrateOptions) error {
+	return nil
+}
+
+func (s *stubMigrator) ResumeCheckpoint(string) (migrate.Checkpoint, error) {
+	return s.resumeCheckpoint, s.resumeCheckpointErr
+}
+
+var _ = Describe("Migrate", func() {
+	It("rejects a call missing the required positional arguments", func() {
+		err := plugin.Migrate(&stubMigrator{}, []string{})
+		Expect(err).To(MatchError(ContainSubstring("required arguments")))
+	})
+
+	It("rejects unexpected extra arguments", func() {
+		err := plugin.Migrate(&stubMigrator{}, []string{"donor", "db-small", "extra"})
+		Expect(err).To(MatchError(ContainSubstring("unexpected arguments: extra")))
+	})
+
+	It("bypasses the positional-argument check when --resume is given", func() {
+		migrator := &stubMigrator{
+			resumeCheckpoint: migrate.Checkpoint{Phase: migrate.PhaseRenamed},
+		}
+
+		err := plugin.Migrate(migrator, []string{"--resume=/tmp/some-checkpoint.json"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(migrator.createInstanceCalls).To(Equal(0))
+		Expect(migrator.migrateDataCalls).To(Equal(0))
+		Expect(migrator.renameCalls).To(Equal(0))
+	})
+
+	It("re-creates the recipient service instance when resuming from the started phase", func() {
+		migrator := &stubMigrator{
+			resumeCheckpoint: migrate.Checkpoint{Phase: migrate.PhaseStarted},
+		}
+
+		err := plugin.Migrate(migrator, []string{"--resume=/tmp/some-checkpoint.json"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(migrator.createInstanceCalls).To(Equal(1))
+		Expect(migrator.migrateDataCalls).To(Equal(1))
+		Expect(migrator.renameCalls).To(Equal(1))
+	})
+
+	It("does not re-create the recipient service instance when resuming from a later phase", func() {
+		migrator := &stubMigrator{
+			resumeCheckpoint: migrate.Checkpoint{Phase: migrate.PhaseBoundDonor},
+		}
+
+		err := plugin.Migrate(migrator, []string{"--resume=/tmp/some-checkpoint.json"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(migrator.createInstanceCalls).To(Equal(0))
+		Expect(migrator.migrateDataCalls).To(Equal(1))
+		Expect(migrator.renameCalls).To(Equal(1))
+	})
+})
+
+var _ = Describe("Migrations", func() {
+	It("requires a subcommand", func() {
+		err := plugin.Migrations([]string{})
+		Expect(err).To(MatchError(ContainSubstring("expected a subcommand")))
+	})
+
+	It("rejects an unknown subcommand", func() {
+		err := plugin.Migrations([]string{"frobnicate"})
+		Expect(err).To(MatchError(ContainSubstring("unknown subcommand")))
+	})
+
+	Describe("list, show, and abort", func() {
+		var (
+			originalHome   string
+			tmpHome        string
+			checkpointPath string
+		)
+
+		BeforeEach(func() {
+			var err error
+			tmpHome, err = ioutil.TempDir("", "migrations-test-home")
+			Expect(err).NotTo(HaveOccurred())
+
+			originalHome = os.Getenv("HOME")
+			Expect(os.Setenv("HOME", tmpHome)).To(Succeed())
+
+			checkpointPath, err = migrate.DefaultCheckpointPath("donor-instance")
+			Expect(err).NotTo(HaveOccurred())
+
+			checkpoint := migrate.Checkpoint{
+				Path:                  checkpointPath,
+				DonorInstanceName:     "donor-instance",
+				RecipientInstanceName: "recipient-instance",
+				Phase:                 migrate.PhaseBoundDonor,
+			}
+			Expect(migrate.NewDiskPersistor(checkpointPath).Save(&checkpoint)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.Setenv("HOME", originalHome)).To(Succeed())
+			os.RemoveAll(tmpHome)
+		})
+
+		It("lists every checkpoint found in the default checkpoint directory", func() {
+			out := captureStdout(func() {
+				Expect(plugin.Migrations([]string{"list"})).To(Succeed())
+			})
+
+			Expect(out).To(ContainSubstring("donor-instance"))
+			Expect(out).To(ContainSubstring("recipient-instance"))
+			Expect(out).To(ContainSubstring(migrate.PhaseBoundDonor))
+		})
+
+		It("prints the full checkpoint when shown by path", func() {
+			out := captureStdout(func() {
+				Expect(plugin.Migrations([]string{"show", checkpointPath})).To(Succeed())
+			})
+
+			Expect(out).To(ContainSubstring(`"donor_instance_name": "donor-instance"`))
+		})
+
+		It("requires exactly one checkpoint path to show", func() {
+			err := plugin.Migrations([]string{"show"})
+			Expect(err).To(MatchError(ContainSubstring("show requires a checkpoint file")))
+		})
+
+		It("deletes the checkpoint file when aborted", func() {
+			captureStdout(func() {
+				Expect(plugin.Migrations([]string{"abort", checkpointPath})).To(Succeed())
+			})
+
+			_, err := os.Stat(checkpointPath)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("requires exactly one checkpoint path to abort", func() {
+			err := plugin.Migrations([]string{"abort"})
+			Expect(err).To(MatchError(ContainSubstring("abort requires a checkpoint file")))
+		})
+	})
+})
+
+var _ = Describe("FindBindings", func() {
+	It("rejects --space without --org", func() {
+		err := plugin.FindBindings(&findbindingsfakes.FakeCFClient{}, []string{"--space=my-space", "p.mysql"})
+		Expect(err).To(MatchError("--space requires --org"))
+	})
+})
@@ -0,0 +1,47 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package unpack
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/gobuffalo/packr"
+	"github.com/pkg/errors"
+)
+
+type Unpacker struct {
+	box packr.Box
+}
+
+func NewUnpacker() *Unpacker {
+	return &Unpacker{
+		box: packr.NewBox("./migrate-app"),
+	}
+}
+
+func (u *Unpacker) Unpack(destDir string) error {
+	return u.box.Walk(func(path string, file packr.File) error {
+		contents, err := ioutil.ReadAll(file)
+		if err != nil {
+			return errors.Wrapf(err, "error reading packed asset %s", path)
+		}
+
+		destPath := filepath.Join(destDir, path)
+		if err := ioutil.WriteFile(destPath, contents, 0755); err != nil {
+			return errors.Wrapf(err, "error writing asset %s", destPath)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+)
+
+// LogicalDumpMigrator mysqldumps the donor with --routines --triggers --events so
+// that the recipient ends up with a byte-for-byte logical copy of the schema, then
+// checks row counts table-by-table to catch a dump/restore that silently dropped rows.
+type LogicalDumpMigrator struct{}
+
+func NewLogicalDumpMigrator() *LogicalDumpMigrator {
+	return &LogicalDumpMigrator{}
+}
+
+func (m *LogicalDumpMigrator) Migrate(donor, recipient Credentials, filter schemaFilter) error {
+	schemas, err := userSchemas(donor, filter)
+	if err != nil {
+		return errors.Wrap(err, "error listing donor schemas")
+	}
+
+	if len(schemas) == 0 {
+		return errors.New("no schemas matched the configured schema filter")
+	}
+
+	dumpArgs := []string{
+		"-h", donor.Hostname,
+		"-P", fmt.Sprintf("%d", donor.Port),
+		"-u", donor.Username,
+		fmt.Sprintf("-p%s", donor.Password),
+		"--routines",
+		"--triggers",
+		"--events",
+		"--single-transaction",
+		"--set-gtid-purged=OFF",
+		"--hex-blob",
+		"--databases",
+	}
+	dumpArgs = append(dumpArgs, schemas...)
+
+	dump := exec.Command("mysqldump", dumpArgs...)
+	load := exec.Command("mysql",
+		"-h", recipient.Hostname,
+		"-P", fmt.Sprintf("%d", recipient.Port),
+		"-u", recipient.Username,
+		fmt.Sprintf("-p%s", recipient.Password),
+	)
+
+	if err := pipe(dump, load); err != nil {
+		return err
+	}
+
+	return validateRowCounts(donor, recipient, schemas)
+}
+
+func userSchemas(donor Credentials, filter schemaFilter) ([]string, error) {
+	db, err := connect(donor)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT schema_name FROM information_schema.schemata
+		WHERE schema_name NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`)
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying information_schema")
+	}
+	defer rows.Close()
+
+	var all []string
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return nil, err
+		}
+		all = append(all, schema)
+	}
+
+	return applySchemaFilter(all, filter), nil
+}
+
+func applySchemaFilter(schemas []string, filter schemaFilter) []string {
+	included := func(schema string) bool {
+		if len(filter.include) == 0 {
+			return true
+		}
+		for _, s := range filter.include {
+			if s == schema {
+				return true
+			}
+		}
+		return false
+	}
+
+	excluded := func(schema string) bool {
+		for _, s := range filter.exclude {
+			if s == schema {
+				return true
+			}
+		}
+		return false
+	}
+
+	var filtered []string
+	for _, schema := range schemas {
+		if included(schema) && !excluded(schema) {
+			filtered = append(filtered, schema)
+		}
+	}
+
+	return filtered
+}
+
+func validateRowCounts(donor, recipient Credentials, schemas []string) error {
+	donorDB, err := connect(donor)
+	if err != nil {
+		return err
+	}
+	defer donorDB.Close()
+
+	recipientDB, err := connect(recipient)
+	if err != nil {
+		return err
+	}
+	defer recipientDB.Close()
+
+	tables, err := tablesIn(donorDB, schemas)
+	if err != nil {
+		return errors.Wrap(err, "error listing donor tables")
+	}
+
+	var mismatches []string
+	for _, table := range tables {
+		donorCount, err := rowCount(donorDB, table)
+		if err != nil {
+			return errors.Wrapf(err, "error counting rows in donor table %s", table)
+		}
+
+		recipientCount, err := rowCount(recipientDB, table)
+		if err != nil {
+			return errors.Wrapf(err, "error counting rows in recipient table %s", table)
+		}
+
+		if donorCount != recipientCount {
+			mismatches = append(mismatches, fmt.Sprintf("%s: donor=%d recipient=%d", table, donorCount, recipientCount))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("row count validation failed for %d table(s):\n%s", len(mismatches), strings.Join(mismatches, "\n"))
+	}
+
+	fmt.Fprintf(os.Stdout, "Validated row counts for %d table(s) across %d schema(s)\n", len(tables), len(schemas))
+	return nil
+}
+
+func tablesIn(db *sql.DB, schemas []string) ([]string, error) {
+	var tables []string
+	for _, schema := range schemas {
+		rows, err := db.Query(`SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_type = 'BASE TABLE'`, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var table string
+			if err := rows.Scan(&table); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			tables = append(tables, fmt.Sprintf("`%s`.`%s`", schema, table))
+		}
+		rows.Close()
+	}
+
+	return tables, nil
+}
+
+func rowCount(db *sql.DB, qualifiedTable string) (int64, error) {
+	var count int64
+	err := db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", qualifiedTable)).Scan(&count)
+	return count, err
+}
+
+func connect(creds Credentials) (*sql.DB, error) {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/", creds.Username, creds.Password, creds.Hostname, creds.Port)
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening connection")
+	}
+
+	return db, nil
+}
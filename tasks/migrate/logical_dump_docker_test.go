@@ -0,0 +1,161 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package main_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+	_ "github.com/go-sql-driver/mysql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+// hostPort returns the host-mapped port the container's MySQL port was
+// published on, so the test can reach it from outside the docker network.
+func hostPort(container *docker.Container) int {
+	bindings := container.NetworkSettings.Ports[mySQLDockerPort]
+	Expect(bindings).NotTo(BeEmpty())
+
+	port, err := strconv.Atoi(bindings[0].HostPort)
+	Expect(err).NotTo(HaveOccurred())
+
+	return port
+}
+
+func connectToHostPort(port int) *sql.DB {
+	db, err := sql.Open("mysql", fmt.Sprintf("root@tcp(127.0.0.1:%d)/", port))
+	Expect(err).NotTo(HaveOccurred())
+
+	Eventually(db.Ping, 2*time.Minute, time.Second).Should(Succeed())
+
+	return db
+}
+
+var _ = Describe("logical-dump migration", func() {
+	var (
+		donorContainer, recipientContainer *docker.Container
+		donorPort, recipientPort           int
+		donorDB, recipientDB               *sql.DB
+	)
+
+	BeforeEach(func() {
+		var err error
+		donorContainer, err = createMySQLContainer("donor")
+		Expect(err).NotTo(HaveOccurred())
+
+		recipientContainer, err = createMySQLContainer("recipient")
+		Expect(err).NotTo(HaveOccurred())
+
+		donorPort = hostPort(donorContainer)
+		recipientPort = hostPort(recipientContainer)
+
+		donorDB = connectToHostPort(donorPort)
+		recipientDB = connectToHostPort(recipientPort)
+	})
+
+	AfterEach(func() {
+		if donorDB != nil {
+			donorDB.Close()
+		}
+		if recipientDB != nil {
+			recipientDB.Close()
+		}
+		dockerClient.RemoveContainer(docker.RemoveContainerOptions{ID: donorContainer.ID, Force: true})
+		dockerClient.RemoveContainer(docker.RemoveContainerOptions{ID: recipientContainer.ID, Force: true})
+	})
+
+	It("preserves triggers, routines, and events when migrating with --mode=logical-dump", func() {
+		_, err := donorDB.Exec(`CREATE DATABASE logicaldumptest`)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = donorDB.Exec(`CREATE TABLE logicaldumptest.widgets (id INT PRIMARY KEY, name VARCHAR(32))`)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = donorDB.Exec(`INSERT INTO logicaldumptest.widgets (id, name) VALUES (1, 'gadget')`)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = donorDB.Exec(`CREATE TRIGGER logicaldumptest.trg_widgets BEFORE INSERT ON logicaldumptest.widgets
+			FOR EACH ROW SET NEW.name = UPPER(NEW.name)`)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = donorDB.Exec(`CREATE PROCEDURE logicaldumptest.count_widgets() SELECT COUNT(*) FROM logicaldumptest.widgets`)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = donorDB.Exec(`SET GLOBAL event_scheduler = ON`)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = donorDB.Exec(`CREATE EVENT logicaldumptest.noop_event ON SCHEDULE EVERY 1 DAY DO SELECT 1`)
+		Expect(err).NotTo(HaveOccurred())
+
+		vcapServices, err := json.Marshal(map[string][]map[string]interface{}{
+			"p.mysql": {
+				{
+					"name": "donor-instance",
+					"credentials": map[string]interface{}{
+						"hostname": "127.0.0.1",
+						"port":     donorPort,
+						"username": "root",
+						"password": "",
+					},
+				},
+				{
+					"name": "recipient-instance",
+					"credentials": map[string]interface{}{
+						"hostname": "127.0.0.1",
+						"port":     recipientPort,
+						"username": "root",
+						"password": "",
+					},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		cmd := exec.Command(migrateTaskBinPath, "--mode=logical-dump", "donor-instance", "recipient-instance")
+		cmd.Env = append(os.Environ(),
+			"VCAP_APPLICATION={}",
+			"VCAP_SERVICES="+string(vcapServices),
+		)
+
+		session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(session, 2*time.Minute).Should(gexec.Exit(0))
+
+		var triggerCount int
+		Expect(recipientDB.QueryRow(`SELECT COUNT(*) FROM information_schema.triggers
+			WHERE trigger_schema = 'logicaldumptest' AND trigger_name = 'trg_widgets'`).Scan(&triggerCount)).To(Succeed())
+		Expect(triggerCount).To(Equal(1))
+
+		var routineCount int
+		Expect(recipientDB.QueryRow(`SELECT COUNT(*) FROM information_schema.routines
+			WHERE routine_schema = 'logicaldumptest' AND routine_name = 'count_widgets'`).Scan(&routineCount)).To(Succeed())
+		Expect(routineCount).To(Equal(1))
+
+		var eventCount int
+		Expect(recipientDB.QueryRow(`SELECT COUNT(*) FROM information_schema.events
+			WHERE event_schema = 'logicaldumptest' AND event_name = 'noop_event'`).Scan(&eventCount)).To(Succeed())
+		Expect(eventCount).To(Equal(1))
+
+		var rowCount int
+		Expect(recipientDB.QueryRow(`SELECT COUNT(*) FROM logicaldumptest.widgets`).Scan(&rowCount)).To(Succeed())
+		Expect(rowCount).To(Equal(1))
+	})
+})
@@ -0,0 +1,229 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	cfenv "github.com/cloudfoundry-community/go-cfenv"
+)
+
+func main() {
+	var (
+		mode          string
+		includeSchema string
+		excludeSchema string
+		preflight     bool
+		plan          string
+		listSchemas   bool
+		schema        string
+	)
+
+	flag.StringVar(&mode, "mode", "streaming", "migration strategy: streaming or logical-dump")
+	flag.StringVar(&includeSchema, "include-schema", "", "comma-separated list of schemas to migrate (default: all user schemas)")
+	flag.StringVar(&excludeSchema, "exclude-schema", "", "comma-separated list of schemas to exclude from migration")
+	flag.BoolVar(&preflight, "preflight", false, "run preflight checks against the donor instance and report findings instead of migrating")
+	flag.StringVar(&plan, "plan", "", "target plan name, used to estimate disk headroom during preflight checks")
+	flag.BoolVar(&listSchemas, "list-schemas", false, "print the user schemas that would be migrated and exit, instead of migrating")
+	flag.StringVar(&schema, "schema", "", "migrate only this single schema, reporting its progress as it runs (used for per-schema parallel migration)")
+	flag.Parse()
+
+	appEnv, err := cfenv.Current()
+	if err != nil {
+		log.Fatalf("error reading application environment: %s", err)
+	}
+
+	if preflight {
+		args := flag.Args()
+		if len(args) != 1 {
+			log.Fatal("Usage: migrate --preflight --plan=<plan-name> <donor-instance-name>")
+		}
+
+		donor, err := credentialsFor(appEnv, args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		runPreflight(donor, plan)
+		return
+	}
+
+	args := flag.Args()
+	if len(args) != 2 {
+		log.Fatal("Usage: migrate [--mode=streaming|logical-dump] <donor-instance-name> <recipient-instance-name>")
+	}
+	donorInstanceName, recipientInstanceName := args[0], args[1]
+
+	donor, err := credentialsFor(appEnv, donorInstanceName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	recipient, err := credentialsFor(appEnv, recipientInstanceName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filter := schemaFilter{
+		include: splitAndTrim(includeSchema),
+		exclude: splitAndTrim(excludeSchema),
+	}
+
+	if listSchemas {
+		schemas, err := userSchemas(donor, filter)
+		if err != nil {
+			log.Fatalf("error listing donor schemas: %s", err)
+		}
+
+		out, err := json.Marshal(schemas)
+		if err != nil {
+			log.Fatalf("error marshalling schema list: %s", err)
+		}
+
+		fmt.Printf("%s%s\n", schemaListMarker, out)
+		return
+	}
+
+	if schema != "" {
+		filter = schemaFilter{include: []string{schema}}
+	}
+
+	var migrator interface {
+		Migrate(donor, recipient Credentials, filter schemaFilter) error
+	}
+
+	switch mode {
+	case "logical-dump":
+		migrator = NewLogicalDumpMigrator()
+	case "streaming":
+		migrator = NewStreamingMigrator()
+	default:
+		log.Fatalf("unrecognized migration mode %q", mode)
+	}
+
+	if schema != "" {
+		reportProgress(schema, "running", 0, nil)
+		started := time.Now()
+		if err := migrator.Migrate(donor, recipient, filter); err != nil {
+			reportProgress(schema, "failed", time.Since(started), err)
+			log.Fatalf("migration failed: %s", err)
+		}
+
+		reportProgress(schema, "succeeded", time.Since(started), nil)
+		fmt.Println("Migration completed successfully")
+		return
+	}
+
+	if err := migrator.Migrate(donor, recipient, filter); err != nil {
+		log.Fatalf("migration failed: %s", err)
+	}
+
+	fmt.Println("Migration completed successfully")
+}
+
+// schemaListMarker and schemaProgressMarker prefix structured JSON that the
+// plugin scans out of this app's `cf logs --recent` output (the schema list
+// when enumerating schemas, progress lines in its TaskReporter), the same
+// trick Preflight's reportMarker uses to get data out of a CF task.
+const (
+	schemaListMarker     = "SCHEMA_LIST:"
+	schemaProgressMarker = "SCHEMA_PROGRESS:"
+)
+
+type schemaProgress struct {
+	Schema  string `json:"schema"`
+	Status  string `json:"status"`
+	Elapsed string `json:"elapsed,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func reportProgress(schema, status string, elapsed time.Duration, err error) {
+	progress := schemaProgress{Schema: schema, Status: status}
+	if elapsed > 0 {
+		progress.Elapsed = elapsed.Round(time.Second).String()
+	}
+	if err != nil {
+		progress.Error = err.Error()
+	}
+
+	out, marshalErr := json.Marshal(progress)
+	if marshalErr != nil {
+		log.Printf("error marshalling schema progress: %s", marshalErr)
+		return
+	}
+
+	fmt.Printf("%s%s\n", schemaProgressMarker, out)
+}
+
+// Credentials are the connection details for a bound p.mysql service instance.
+type Credentials struct {
+	Hostname string
+	Port     int
+	Name     string
+	Username string
+	Password string
+}
+
+func credentialsFor(appEnv *cfenv.App, instanceName string) (Credentials, error) {
+	service, err := appEnv.Services.WithName(instanceName)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("error finding credentials for service instance %q: %s", instanceName, err)
+	}
+
+	hostname, ok := service.Credentials["hostname"].(string)
+	if !ok {
+		return Credentials{}, fmt.Errorf("service instance %q is missing a hostname credential", instanceName)
+	}
+
+	dbName, _ := service.Credentials["name"].(string)
+	username, _ := service.Credentials["username"].(string)
+	password, _ := service.Credentials["password"].(string)
+
+	port := 3306
+	if p, ok := service.Credentials["port"].(float64); ok {
+		port = int(p)
+	}
+
+	return Credentials{
+		Hostname: hostname,
+		Port:     port,
+		Name:     dbName,
+		Username: username,
+		Password: password,
+	}, nil
+}
+
+type schemaFilter struct {
+	include []string
+	exclude []string
+}
+
+func splitAndTrim(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if trimmed := strings.TrimSpace(v); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+const reportMarker = "PREFLIGHT_REPORT:"
+
+type severity string
+
+const (
+	severityError   severity = "ERROR"
+	severityWarning severity = "WARNING"
+)
+
+type finding struct {
+	Severity severity
+	Category string
+	Message  string
+}
+
+type report struct {
+	Findings []finding
+}
+
+func (r *report) add(s severity, category, format string, args ...interface{}) {
+	r.Findings = append(r.Findings, finding{
+		Severity: s,
+		Category: category,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// planDiskQuotaMB is a best-effort lookup of p.mysql plan disk quotas, in megabytes,
+// used to sanity-check the donor's estimated dump size. Plans not listed here are skipped
+// with a WARNING rather than guessed at.
+var planDiskQuotaMB = map[string]int64{
+	"db-small":  10240,
+	"db-medium": 20480,
+	"db-large":  40960,
+}
+
+func runPreflight(donor Credentials, plan string) {
+	r := &report{}
+
+	db, err := connect(donor)
+	if err != nil {
+		log.Fatalf("error connecting to donor instance: %s", err)
+	}
+	defer db.Close()
+
+	checkEngines(db, r)
+	checkDefiners(db, r)
+	checkGTIDState(db, r)
+	checkCharsetsAndCollations(db, r)
+	checkFilePerTable(db, r)
+	checkReservedWords(db, r)
+	checkDiskQuota(db, plan, r)
+
+	out, err := json.Marshal(r)
+	if err != nil {
+		log.Fatalf("error marshalling preflight report: %s", err)
+	}
+
+	fmt.Printf("%s%s\n", reportMarker, out)
+}
+
+func checkEngines(db *sql.DB, r *report) {
+	rows, err := db.Query(`SELECT table_schema, table_name, engine FROM information_schema.tables
+		WHERE table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+		AND engine IS NOT NULL AND engine != 'InnoDB'`)
+	if err != nil {
+		r.add(severityWarning, "engine", "unable to check table engines: %s", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schema, table, engine string
+		if err := rows.Scan(&schema, &table, &engine); err != nil {
+			continue
+		}
+		r.add(severityError, "engine", "table `%s`.`%s` uses engine %s, only InnoDB is supported", schema, table, engine)
+	}
+}
+
+func checkDefiners(db *sql.DB, r *report) {
+	rows, err := db.Query(`SELECT table_schema, table_name, definer FROM information_schema.views
+		WHERE table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`)
+	if err != nil {
+		r.add(severityWarning, "definer", "unable to check view definers: %s", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schema, view, definer string
+		if err := rows.Scan(&schema, &view, &definer); err != nil {
+			continue
+		}
+		r.add(severityWarning, "definer", "view `%s`.`%s` is locked to definer %s, which may not exist on the recipient", schema, view, definer)
+	}
+}
+
+func checkGTIDState(db *sql.DB, r *report) {
+	var variable, value string
+	if err := db.QueryRow(`SHOW VARIABLES LIKE 'gtid_mode'`).Scan(&variable, &value); err != nil {
+		r.add(severityWarning, "gtid", "unable to determine gtid_mode: %s", err)
+		return
+	}
+
+	if value != "ON" {
+		r.add(severityError, "gtid", "gtid_mode is %s, expected ON for a consistent migration", value)
+	}
+}
+
+func checkCharsetsAndCollations(db *sql.DB, r *report) {
+	rows, err := db.Query(`SELECT table_schema, table_name, ccsa.character_set_name FROM information_schema.tables t
+		JOIN information_schema.collation_character_set_applicability ccsa ON t.table_collation = ccsa.collation_name
+		WHERE t.table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+		AND ccsa.character_set_name NOT IN ('utf8', 'utf8mb4', 'latin1')`)
+	if err != nil {
+		r.add(severityWarning, "charset", "unable to check table charsets: %s", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schema, table, charset string
+		if err := rows.Scan(&schema, &table, &charset); err != nil {
+			continue
+		}
+		r.add(severityWarning, "charset", "table `%s`.`%s` uses charset %s, verify it is supported on the target", schema, table, charset)
+	}
+}
+
+func checkFilePerTable(db *sql.DB, r *report) {
+	var variable, value string
+	if err := db.QueryRow(`SHOW VARIABLES LIKE 'innodb_file_per_table'`).Scan(&variable, &value); err != nil {
+		r.add(severityWarning, "tablespace", "unable to determine innodb_file_per_table: %s", err)
+		return
+	}
+
+	if value != "ON" {
+		r.add(severityWarning, "tablespace", "innodb_file_per_table is OFF; per-table dumps may be slower to restore")
+	}
+}
+
+var reservedWords = map[string]bool{
+	"rank": true, "lateral": true, "groups": true, "recursive": true, "system": true, "window": true,
+}
+
+func checkReservedWords(db *sql.DB, r *report) {
+	rows, err := db.Query(`SELECT table_schema, table_name, column_name FROM information_schema.columns
+		WHERE table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`)
+	if err != nil {
+		r.add(severityWarning, "reserved-word", "unable to check column names: %s", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schema, table, column string
+		if err := rows.Scan(&schema, &table, &column); err != nil {
+			continue
+		}
+		if reservedWords[column] {
+			r.add(severityWarning, "reserved-word", "column `%s`.`%s`.`%s` is a reserved word on the target MySQL version", schema, table, column)
+		}
+	}
+}
+
+func checkDiskQuota(db *sql.DB, plan string, r *report) {
+	quotaMB, ok := planDiskQuotaMB[plan]
+	if !ok {
+		r.add(severityWarning, "disk-quota", "unknown plan %q, unable to check estimated dump size against its disk quota", plan)
+		return
+	}
+
+	var sizeBytes sql.NullFloat64
+	err := db.QueryRow(`SELECT SUM(data_length + index_length) FROM information_schema.tables
+		WHERE table_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`).Scan(&sizeBytes)
+	if err != nil {
+		r.add(severityWarning, "disk-quota", "unable to estimate donor size: %s", err)
+		return
+	}
+
+	sizeMB := int64(sizeBytes.Float64) / 1024 / 1024
+	if sizeMB > quotaMB {
+		r.add(severityError, "disk-quota", "estimated dump size %dMB exceeds plan %q disk quota of %dMB", sizeMB, plan, quotaMB)
+	}
+}
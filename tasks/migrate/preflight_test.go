@@ -0,0 +1,163 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// newMockDB returns a *sql.DB backed by sqlmock, along with the mock used to
+// script its expected queries, for unit-testing the check* functions without
+// a real MySQL server.
+func newMockDB() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	Expect(err).NotTo(HaveOccurred())
+	return db, mock
+}
+
+var _ = Describe("preflight checks", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		r    *report
+	)
+
+	BeforeEach(func() {
+		db, mock = newMockDB()
+		r = &report{}
+	})
+
+	AfterEach(func() {
+		db.Close()
+	})
+
+	Describe("checkEngines", func() {
+		It("reports an ERROR for every non-InnoDB table", func() {
+			mock.ExpectQuery("FROM information_schema.tables").
+				WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "engine"}).
+					AddRow("app", "widgets", "MyISAM"))
+
+			checkEngines(db, r)
+
+			Expect(r.Findings).To(ConsistOf(finding{
+				Severity: severityError,
+				Category: "engine",
+				Message:  "table `app`.`widgets` uses engine MyISAM, only InnoDB is supported",
+			}))
+		})
+
+		It("reports a WARNING when the query itself fails", func() {
+			mock.ExpectQuery("FROM information_schema.tables").WillReturnError(fmt.Errorf("connection reset"))
+
+			checkEngines(db, r)
+
+			Expect(r.Findings).To(HaveLen(1))
+			Expect(r.Findings[0].Severity).To(Equal(severityWarning))
+		})
+	})
+
+	Describe("checkGTIDState", func() {
+		It("reports an ERROR when gtid_mode is not ON", func() {
+			mock.ExpectQuery("SHOW VARIABLES LIKE 'gtid_mode'").
+				WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("gtid_mode", "OFF"))
+
+			checkGTIDState(db, r)
+
+			Expect(r.Findings).To(ConsistOf(finding{
+				Severity: severityError,
+				Category: "gtid",
+				Message:  "gtid_mode is OFF, expected ON for a consistent migration",
+			}))
+		})
+
+		It("reports nothing when gtid_mode is ON", func() {
+			mock.ExpectQuery("SHOW VARIABLES LIKE 'gtid_mode'").
+				WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("gtid_mode", "ON"))
+
+			checkGTIDState(db, r)
+
+			Expect(r.Findings).To(BeEmpty())
+		})
+	})
+
+	Describe("checkFilePerTable", func() {
+		It("warns when innodb_file_per_table is OFF", func() {
+			mock.ExpectQuery("SHOW VARIABLES LIKE 'innodb_file_per_table'").
+				WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("innodb_file_per_table", "OFF"))
+
+			checkFilePerTable(db, r)
+
+			Expect(r.Findings).To(HaveLen(1))
+			Expect(r.Findings[0].Severity).To(Equal(severityWarning))
+		})
+	})
+
+	Describe("checkReservedWords", func() {
+		It("warns only about columns that match a reserved word", func() {
+			mock.ExpectQuery("FROM information_schema.columns").
+				WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "column_name"}).
+					AddRow("app", "widgets", "name").
+					AddRow("app", "widgets", "rank"))
+
+			checkReservedWords(db, r)
+
+			Expect(r.Findings).To(ConsistOf(finding{
+				Severity: severityWarning,
+				Category: "reserved-word",
+				Message:  "column `app`.`widgets`.`rank` is a reserved word on the target MySQL version",
+			}))
+		})
+	})
+
+	Describe("checkDiskQuota", func() {
+		It("skips the check with a WARNING for an unrecognized plan", func() {
+			checkDiskQuota(db, "db-unknown-plan", r)
+
+			Expect(r.Findings).To(ConsistOf(finding{
+				Severity: severityWarning,
+				Category: "disk-quota",
+				Message:  `unknown plan "db-unknown-plan", unable to check estimated dump size against its disk quota`,
+			}))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("reports an ERROR when the estimated dump size exceeds the plan's quota", func() {
+			oversizeBytes := float64(11000) * 1024 * 1024
+			mock.ExpectQuery("FROM information_schema.tables").
+				WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(oversizeBytes))
+
+			checkDiskQuota(db, "db-small", r)
+
+			Expect(r.Findings).To(ConsistOf(finding{
+				Severity: severityError,
+				Category: "disk-quota",
+				Message:  "estimated dump size 11000MB exceeds plan \"db-small\" disk quota of 10240MB",
+			}))
+		})
+
+		It("reports nothing when the estimated dump size is within the plan's quota", func() {
+			withinQuotaBytes := float64(1024) * 1024 * 1024
+			mock.ExpectQuery("FROM information_schema.tables").
+				WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(withinQuotaBytes))
+
+			checkDiskQuota(db, "db-small", r)
+
+			Expect(r.Findings).To(BeEmpty())
+		})
+	})
+})
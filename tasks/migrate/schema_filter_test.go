@@ -0,0 +1,46 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("applySchemaFilter", func() {
+	schemas := []string{"app1", "app2", "app3"}
+
+	It("returns every schema when no filter is configured", func() {
+		Expect(applySchemaFilter(schemas, schemaFilter{})).To(Equal(schemas))
+	})
+
+	It("keeps only the schemas named in include", func() {
+		filter := schemaFilter{include: []string{"app1", "app3"}}
+		Expect(applySchemaFilter(schemas, filter)).To(Equal([]string{"app1", "app3"}))
+	})
+
+	It("drops the schemas named in exclude", func() {
+		filter := schemaFilter{exclude: []string{"app2"}}
+		Expect(applySchemaFilter(schemas, filter)).To(Equal([]string{"app1", "app3"}))
+	})
+
+	It("applies exclude on top of include, even if a schema appears in both", func() {
+		filter := schemaFilter{include: []string{"app1", "app2"}, exclude: []string{"app2"}}
+		Expect(applySchemaFilter(schemas, filter)).To(Equal([]string{"app1"}))
+	})
+
+	It("returns no schemas when include matches none of them", func() {
+		filter := schemaFilter{include: []string{"app4"}}
+		Expect(applySchemaFilter(schemas, filter)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,92 @@
+// Copyright (C) 2018-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under the terms of the under the Apache License,
+// Version 2.0 (the "License”); you may not use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on
+// an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// StreamingMigrator pipes a plain mysqldump of table data from the donor straight
+// into the recipient. It does not carry over triggers, routines, or events.
+type StreamingMigrator struct{}
+
+func NewStreamingMigrator() *StreamingMigrator {
+	return &StreamingMigrator{}
+}
+
+func (m *StreamingMigrator) Migrate(donor, recipient Credentials, filter schemaFilter) error {
+	dumpArgs := []string{
+		"-h", donor.Hostname,
+		"-P", fmt.Sprintf("%d", donor.Port),
+		"-u", donor.Username,
+		fmt.Sprintf("-p%s", donor.Password),
+		"--single-transaction",
+		"--set-gtid-purged=OFF",
+	}
+
+	if len(filter.include) == 0 && len(filter.exclude) == 0 {
+		dumpArgs = append(dumpArgs, "--all-databases")
+	} else {
+		schemas, err := userSchemas(donor, filter)
+		if err != nil {
+			return errors.Wrap(err, "error listing donor schemas")
+		}
+
+		if len(schemas) == 0 {
+			return errors.New("no schemas matched the configured schema filter")
+		}
+
+		dumpArgs = append(dumpArgs, "--databases")
+		dumpArgs = append(dumpArgs, schemas...)
+	}
+
+	dump := exec.Command("mysqldump", dumpArgs...)
+
+	load := exec.Command("mysql",
+		"-h", recipient.Hostname,
+		"-P", fmt.Sprintf("%d", recipient.Port),
+		"-u", recipient.Username,
+		fmt.Sprintf("-p%s", recipient.Password),
+	)
+
+	return pipe(dump, load)
+}
+
+func pipe(producer, consumer *exec.Cmd) error {
+	pipeOut, err := producer.StdoutPipe()
+	if err != nil {
+		return errors.Wrap(err, "error wiring up dump/restore pipe")
+	}
+	consumer.Stdin = pipeOut
+	producer.Stderr = os.Stderr
+	consumer.Stderr = os.Stderr
+	consumer.Stdout = os.Stdout
+
+	if err := consumer.Start(); err != nil {
+		return errors.Wrap(err, "error starting restore")
+	}
+
+	if err := producer.Run(); err != nil {
+		return errors.Wrap(err, "error running dump")
+	}
+
+	if err := consumer.Wait(); err != nil {
+		return errors.Wrap(err, "error running restore")
+	}
+
+	return nil
+}